@@ -6,7 +6,13 @@ import (
 	"github.com/spf13/cobra"
 	"go.joshhogle.dev/s1cli/internal/app"
 	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/commands/account"
+	"go.joshhogle.dev/s1cli/internal/commands/auth"
+	"go.joshhogle.dev/s1cli/internal/commands/configure"
+	"go.joshhogle.dev/s1cli/internal/commands/deprovision"
+	"go.joshhogle.dev/s1cli/internal/commands/keystore"
 	"go.joshhogle.dev/s1cli/internal/commands/provision"
+	"go.joshhogle.dev/s1cli/internal/commands/user"
 	"go.joshhogle.dev/s1cli/internal/commands/version"
 )
 
@@ -31,7 +37,13 @@ func NewRootCommand(state *app.State) *RootCommand {
 	state.Config().GlobalOptions().BindFlags(&cmd.Command)
 
 	// add commands
+	cmd.AddCommand(&account.NewCommand(state).Command)
+	cmd.AddCommand(&auth.NewCommand(state).Command)
+	cmd.AddCommand(&configure.NewCommand(state).Command)
+	cmd.AddCommand(&deprovision.NewCommand(state).Command)
+	cmd.AddCommand(&keystore.NewCommand(state).Command)
 	cmd.AddCommand(&provision.NewCommand(state).Command)
+	cmd.AddCommand(&user.NewCommand(state).Command)
 	cmd.AddCommand(&version.NewCommand(state).Command)
 
 	return cmd
@@ -4,12 +4,11 @@ import (
 	"encoding/json"
 	goerrors "errors"
 	"fmt"
-	"math/rand"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
 	"go.joshhogle.dev/errorx"
 	"go.joshhogle.dev/s1cli/internal/app"
 	"go.joshhogle.dev/s1cli/internal/errors"
@@ -17,33 +16,55 @@ import (
 
 // S1Client is used to interact with the SentinelOne API.
 type S1Client struct {
-	appState *app.State
-	client   *resty.Client
-	apiKey   string
-	baseURL  string
+	appState    *app.State
+	client      *resty.Client
+	apiKey      string
+	baseURL     string
+	log         *zerolog.Logger
+	rateLimits  *s1RateLimiters
+	retryPolicy s1RetryPolicy
+}
+
+// CheckAPIToken makes sure the API token being used has not expired.
+//
+// A warning is logged if the token expires within the given amount of time. The details of the token are returned
+// so that callers can report them.
+//
+// The following errors are returned by this function:
+// S1APITokenExpired, S1APIError, S1ClientError, S1ClientRequestError
+func (s *S1Client) CheckAPIToken(warnWithin time.Duration) (*S1APITokenDetails, errorx.Error) {
+	details, errx := s.GetAPITokenDetails()
+	if errx != nil {
+		return nil, errx
+	}
+	logger := s.logger().With().Time("expires_at", details.ExpiresAt).Logger()
+	if details.IsExpired() {
+		errx := errors.NewS1APITokenExpired(details.ExpiresAt)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	if details.ExpiresWithin(warnWithin) {
+		logger.Warn().Str("expires_in", time.Until(details.ExpiresAt).Round(time.Minute).String()).
+			Msg("API token expires soon and should be rotated")
+	}
+	return details, nil
 }
 
 // CreateAccount creates a new Account in SentinelOne if it does not already exist.
-func (s *S1Client) CreateAccount(req S1AccountProvisioningRequest) (*S1Account, errorx.Error) {
-	logger := s.appState.Logger().With().Str("account_name", req.AccountName).Logger()
+func (s *S1Client) CreateAccount(req S1AccountProvisioningRequest) (*S1Account, S1ProvisioningAction, errorx.Error) {
+	logger := s.logger().With().Str("account_name", req.AccountName).Logger()
 	account, errx := s.FindAccount(req.AccountName)
 	if errx != nil {
-		return nil, errx
+		return nil, "", errx
 	}
 
 	// configure expiration
-	var expires time.Time
-	dur, err := time.ParseDuration(req.Expires)
-	if err == nil {
-		expires = time.Now().Add(dur)
-	} else {
-		expires, err = time.Parse(time.RFC3339, req.Expires)
-		if err != nil {
-			errx := errors.NewS1ClientError(
-				fmt.Sprintf("failed to parse account expiration time and date '%s'", req.Expires), err)
-			logger.Error().Err(errx).Str("expiration_date", req.Expires).Msg(errx.Error())
-			return nil, errx
-		}
+	expires, err := ParseExpiration(req.Expires)
+	if err != nil {
+		errx := errors.NewS1ClientError(
+			fmt.Sprintf("failed to parse account expiration time and date '%s'", req.Expires), err)
+		logger.Error().Err(errx).Str("expiration_date", req.Expires).Msg(errx.Error())
+		return nil, "", errx
 	}
 
 	// account exists - if it is expired, either reactivate it or return an error
@@ -52,85 +73,94 @@ func (s *S1Client) CreateAccount(req S1AccountProvisioningRequest) (*S1Account,
 		switch account.State {
 		case "active":
 			logger.Info().Str("expires", account.Expiration.String()).Msg("found existing active account")
-			return account, nil
+			return account, S1ProvisioningActionExisting, nil
 		case "expired":
 			if !req.ReactivateAccount {
 				errx := errors.NewS1ClientError(
 					"failed to create account because it is expired and not set to be reactivated",
 					goerrors.New("account already exists"))
 				logger.Error().Err(errx).Msg(errx.Error())
-				return nil, errx
+				return nil, "", errx
 			}
 			if errx := s.ReactivateAccount(account.ID, expires); errx != nil {
-				return nil, errx
+				return nil, "", errx
 			}
-			return account, nil
+			return account, S1ProvisioningActionReactivated, nil
 		default:
 			errx := errors.NewS1ClientError(
 				fmt.Sprintf("failed to create account because it exists and is currently '%s'", account.State),
 				goerrors.New("account already exists"))
 			logger.Error().Err(errx).Msg(errx.Error())
-			return nil, errx
+			return nil, "", errx
 		}
 	}
 
 	// create the new account, good until configured duration expires
 	logger.Info().Msg("creating new account")
+	bundles := []map[string]any{}
+	for _, bundle := range req.Bundles {
+		surfaces := []map[string]any{}
+		for _, surface := range bundle.Surfaces {
+			surfaces = append(surfaces, map[string]any{
+				"count": surface.Count,
+				"name":  surface.Name,
+			})
+		}
+		bundles = append(bundles, map[string]any{
+			"name":     bundle.Name,
+			"surfaces": surfaces,
+		})
+	}
 	modules := []map[string]any{}
 	for _, module := range req.Modules {
 		modules = append(modules, map[string]any{"name": module})
 	}
+	settings := []map[string]any{}
+	for _, setting := range req.Settings {
+		settings = append(settings, map[string]any{
+			"groupName": setting.GroupName,
+			"setting":   setting.Setting,
+		})
+	}
 	body := map[string]any{
 		"data": map[string]any{
 			"name":        req.AccountName,
 			"accountType": req.AccountType,
-			"billingMode": "subscription",
+			"billingMode": req.BillingMode,
 			"expiration":  expires.Format(time.RFC3339),
 			"externalId":  req.ExternalID,
-			"inherits":    true,
+			"inherits":    req.Inherits,
 			"licenses": map[string]any{
-				"bundles": []map[string]any{
-					{
-						"name": req.Bundle,
-						"surfaces": []map[string]any{
-							{
-								"count": req.TotalAgents,
-								"name":  "Total Agents",
-							},
-						},
-					},
-				},
-				"modules": modules,
-				"settings": []map[string]any{
-					{
-						"groupName": "dv_retention",
-						"setting":   "30 Days",
-					},
-					{
-						"groupName": "malicious_data_retention",
-						"setting":   "365 Days",
-					},
-					{
-						"groupName": "remote_shell_availability",
-						"setting":   "Enabled",
-					},
-					{
-						"groupName": "marketplace_access_status",
-						"setting":   "Available",
-					},
-					{
-						"groupName": "account_level_ranger",
-						"setting":   "Account",
-					},
-				},
+				"bundles":  bundles,
+				"modules":  modules,
+				"settings": settings,
 			},
 			"unlimitedExpiration": false,
-			"usageType":           "customer",
+			"usageType":           req.UsageType,
 		},
 	}
-	resp, errx := s.exec(http.MethodPost, "/accounts", withRequestBody(body))
-	if errx != nil {
-		return nil, errx
+	var resp *S1APIResponse
+	for attempt := 1; ; attempt++ {
+		resp, errx = s.exec(http.MethodPost, "/accounts", withRequestBody(body))
+		if errx == nil {
+			break
+		}
+		if !isTransientError(errx) || attempt > s.retryPolicy.maxRetries {
+			return nil, "", errx
+		}
+
+		// the request may have been processed before it failed so only try again if the account does not exist
+		account, errx := s.FindAccount(req.AccountName)
+		if errx != nil {
+			return nil, "", errx
+		}
+		if account != nil {
+			logger.Warn().Str("account_id", account.ID).Msg("account was created even though the request failed")
+			return account, S1ProvisioningActionCreated, nil
+		}
+		wait := s.retryPolicy.backoff(attempt, 0)
+		logger.Warn().Int("attempt", attempt).Dur("wait", wait).Msg("retrying account creation")
+		time.Sleep(wait)
 	}
 
 	// parse the response
@@ -138,54 +168,69 @@ func (s *S1Client) CreateAccount(req S1AccountProvisioningRequest) (*S1Account,
 	if err := json.Unmarshal(resp.Data, &newAcct); err != nil {
 		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
 		logger.Error().Err(errx).Msg(errx.Error())
-		return nil, errx
+		return nil, "", errx
+	}
+	account, errx = s.fromS1APIAccountObject(newAcct)
+	if errx != nil {
+		return nil, "", errx
 	}
-	return s.fromS1APIAccountObject(newAcct)
+	return account, S1ProvisioningActionCreated, nil
 }
 
 // CreateUser creates a new User in SentinelOne if it does not already exist.
-func (s *S1Client) CreateUser(req *S1UserProvisioningRequest, accountID string) (*S1User, errorx.Error) {
-	logger := s.appState.Logger().With().Str("email_address", req.EmailAddress).Logger()
-	user, e := s.FindUser(req.EmailAddress)
+func (s *S1Client) CreateUser(req *S1UserProvisioningRequest, accountID string) (*S1User, S1ProvisioningAction,
+	errorx.Error) {
+
+	logger := s.logger().With().Str("email_address", req.EmailAddress).Logger()
+
+	// the role must exist in the account before the user can be given it
+	role, e := s.FindRole(accountID, req.Role)
 	if e != nil {
-		return nil, e
+		return nil, "", e
+	}
+	if role == nil {
+		errx := errors.NewS1ClientError("failed to find role for user",
+			fmt.Errorf("role '%s' does not exist in account %s", req.Role, accountID))
+		logger.Error().Err(errx).Str("account_id", accountID).Str("role", req.Role).Msg(errx.Error())
+		return nil, "", errx
 	}
-	adminRole, e := s.FindRole(accountID, "Admin")
+	user, e := s.FindUser(req.EmailAddress)
 	if e != nil {
-		return nil, e
+		return nil, "", e
 	}
 
-	// user exists - add the user as an Admin to the account (if they aren't already)
+	// user exists - add the user to the account with the role (if they aren't already in the account)
 	if user != nil {
 		for _, role := range user.ScopeRoles {
 			if role.ScopeID == accountID {
 				logger.Info().Str("user_id", user.ID).Msg("found existing user")
-				return user, nil
+				return user, S1ProvisioningActionExisting, nil
 			}
 		}
 
-		// add the user as an Admin to the account
+		// add the user to the account
 		user.ScopeRoles = append(user.ScopeRoles, S1UserScopeRole{
 			ScopeID:  accountID,
-			RoleID:   adminRole.ID,
-			RoleName: adminRole.Name,
+			RoleID:   role.ID,
+			RoleName: role.Name,
 		})
 		user, err := s.UpdateUserScopeRoles(user.ID, user.ScopeRoles)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
-		return user, nil
+		return user, S1ProvisioningActionAdded, nil
 	}
 
-	// generate random password
-	//rand.Seed(time.Now().UnixNano()) // not required as of Go 1.20
-	chars := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789")
-	length := 32
-	var b strings.Builder
-	for i := 0; i < length; i++ {
-		b.WriteRune(chars[rand.Intn(len(chars))])
+	// generate a random password unless one was given
+	passwd := req.Password
+	if passwd == "" {
+		var err error
+		if passwd, err = DefaultPasswordPolicy().Generate(); err != nil {
+			errx := errors.NewS1ClientError("failed to generate password for user", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return nil, "", errx
+		}
 	}
-	passwd := b.String()
 
 	// create the new user
 	logger.Info().Msg("creating new user")
@@ -197,16 +242,35 @@ func (s *S1Client) CreateUser(req *S1UserProvisioningRequest, accountID string)
 			"scope":    "account",
 			"scopeRoles": []map[string]any{
 				{
-					"id":       accountID,
-					"roleName": req.Role,
+					"id":     accountID,
+					"roleId": role.ID,
 				},
 			},
 			"twoFaEnabled": true,
 		},
 	}
-	resp, err := s.exec(http.MethodPost, "/users", withRequestBody(body))
-	if err != nil {
-		return nil, err
+	var resp *S1APIResponse
+	for attempt := 1; ; attempt++ {
+		resp, e = s.exec(http.MethodPost, "/users", withRequestBody(body))
+		if e == nil {
+			break
+		}
+		if !isTransientError(e) || attempt > s.retryPolicy.maxRetries {
+			return nil, "", e
+		}
+
+		// the request may have been processed before it failed so only try again if the user does not exist
+		user, e := s.FindUser(req.EmailAddress)
+		if e != nil {
+			return nil, "", e
+		}
+		if user != nil {
+			logger.Warn().Str("user_id", user.ID).Msg("user was created even though the request failed")
+			return user, S1ProvisioningActionCreated, nil
+		}
+		wait := s.retryPolicy.backoff(attempt, 0)
+		logger.Warn().Int("attempt", attempt).Dur("wait", wait).Msg("retrying user creation")
+		time.Sleep(wait)
 	}
 
 	// parse the response
@@ -214,15 +278,19 @@ func (s *S1Client) CreateUser(req *S1UserProvisioningRequest, accountID string)
 	if err := json.Unmarshal(resp.Data, &newUser); err != nil {
 		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
 		logger.Error().Err(errx).Msg(errx.Error())
-		return nil, errx
+		return nil, "", errx
+	}
+	user, e = s.fromS1APIUserObject(newUser)
+	if e != nil {
+		return nil, "", e
 	}
-	return s.fromS1APIUserObject(newUser)
+	return user, S1ProvisioningActionCreated, nil
 }
 
-/*
 // DeleteUser deletes an S1 user.
-func (s *S1ClientService) DeleteUser(userID string) *Error {
-	InfoMsg(nil, "deleting user ID '%s'", userID)
+func (s *S1Client) DeleteUser(userID string) errorx.Error {
+	logger := s.logger().With().Str("user_id", userID).Logger()
+	logger.Info().Msg("deleting user")
 
 	body := map[string]any{
 		"filter": map[string]any{
@@ -237,119 +305,315 @@ func (s *S1ClientService) DeleteUser(userID string) *Error {
 	// parse the response
 	var data S1APIAffectedResponseData
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		ErrorMsg(nil, "failed to unmarshal response from server: %s", err.Error())
-		return NewError(ERR_DELETE_USER_FAILED, err)
+		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
 	}
 
 	// make sure the user was deleted
 	if data.Affected == 0 {
-		ErrorMsg(nil, "did not delete any users")
-		return NewError(ERR_DELETE_USER_FAILED, errors.New("did not delete any users"))
+		errx := errors.NewS1ClientError("failed to delete user", goerrors.New("did not delete any users"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	return nil
+}
+
+// ExpireAccount expires the given account immediately.
+func (s *S1Client) ExpireAccount(id string) errorx.Error {
+	logger := s.logger().With().Str("account_id", id).Logger()
+	logger.Info().Msg("expiring account")
+
+	resp, err := s.exec(http.MethodPost, fmt.Sprintf("/accounts/%s/expire-now", id))
+	if err != nil {
+		return err
+	}
+
+	// parse the response
+	var data S1APISuccessResponseData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// make sure account was expired
+	if !data.Success {
+		errx := errors.NewS1ClientError("failed to expire account", goerrors.New("expiration was not successful"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
 	}
 	return nil
 }
-*/
 
 // FindAccount searches for the matching account with the given name.
 //
 // If the account cannot be found, no error will be returned but the account object will be nil.
 func (s *S1Client) FindAccount(name string) (*S1Account, errorx.Error) {
-	logger := s.appState.Logger()
+	logger := s.logger()
 	logger.Debug().Str("account_name", name).Msgf("searching for account")
 
 	// search for the account
 	// -- this should never return more than 1 account as account names must be unique
-	resp, err := s.exec(http.MethodGet, "/accounts", withRequestParams(map[string]string{
-		"name":  name,
-		"limit": "1",
-	}))
-	if err != nil {
-		return nil, err
-	}
+	iter := s.ListAccounts(&S1ListOptions{
+		Filters:  map[string]string{"name": name},
+		MaxItems: 1,
+	})
+	if iter.Next() {
+		return iter.Item(), nil
+	}
+	return nil, iter.Err()
+}
 
-	// parse the data
-	var apiAccounts []S1APIAccountObject
-	if err := json.Unmarshal(resp.Data, &apiAccounts); err != nil {
-		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
-		logger.Error().Err(errx).Msg(errx.Error())
-		return nil, errx
-	}
+// FindAccountByID searches for the account with the given ID.
+//
+// If the account cannot be found, no error will be returned but the account object will be nil.
+func (s *S1Client) FindAccountByID(id string) (*S1Account, errorx.Error) {
+	logger := s.logger()
+	logger.Debug().Str("account_id", id).Msgf("searching for account")
+
+	iter := s.ListAccounts(&S1ListOptions{
+		Filters:  map[string]string{"ids": id},
+		MaxItems: 1,
+	})
+	if iter.Next() {
+		return iter.Item(), nil
+	}
+	return nil, iter.Err()
+}
 
-	// convert the response object
-	if len(apiAccounts) == 0 {
-		return nil, nil
+// FindAccountsByExternalID searches for all of the accounts with the given external ID.
+//
+// The API cannot filter on external IDs so every account is read and checked. External IDs are not required to be
+// unique so more than one account may be returned. If no accounts are found, the list will be empty.
+func (s *S1Client) FindAccountsByExternalID(externalID string) ([]*S1Account, errorx.Error) {
+	logger := s.logger()
+	logger.Debug().Str("external_id", externalID).Msgf("searching for accounts")
+
+	accounts := []*S1Account{}
+	iter := s.ListAccounts(nil)
+	for iter.Next() {
+		if account := iter.Item(); account.ExternalID == externalID {
+			accounts = append(accounts, account)
+		}
+	}
+	if errx := iter.Err(); errx != nil {
+		return nil, errx
 	}
-	return s.fromS1APIAccountObject(apiAccounts[0])
+	return accounts, nil
 }
 
-// FindRole searches for matching roles in the given account with the given name.
+// FindRole searches for matching roles in the given account with the given name or ID.
+//
+// A value made up only of digits is first treated as a role ID before falling back to searching by name.
 //
 // If the role cannot be found, no error will be returned but the role object will be nil.
-func (s *S1Client) FindRole(accountID, name string) (*S1Role, errorx.Error) {
-	logger := s.appState.Logger().With().Str("account_id", accountID).Str("role", name).Logger()
+func (s *S1Client) FindRole(accountID, nameOrID string) (*S1Role, errorx.Error) {
+	logger := s.logger().With().Str("account_id", accountID).Str("role", nameOrID).Logger()
 	logger.Debug().Msg("searching for role in account")
 
-	// search for the role
+	// search for the role by ID
+	if isID(nameOrID) {
+		iter := s.ListRoles(accountID, &S1ListOptions{
+			Filters:  map[string]string{"ids": nameOrID},
+			MaxItems: 1,
+		})
+		if iter.Next() {
+			return iter.Item(), nil
+		}
+		if errx := iter.Err(); errx != nil {
+			return nil, errx
+		}
+	}
+
+	// search for the role by name
 	// -- this should never return more than 1 role as role names must be unique
-	resp, err := s.exec(http.MethodGet, "/rbac/roles", withRequestParams(map[string]string{
-		"accountIds": accountID,
-		"name":       name,
-		"limit":      "1",
-	}))
-	if err != nil {
-		return nil, err
+	iter := s.ListRoles(accountID, &S1ListOptions{
+		Filters:  map[string]string{"name": nameOrID},
+		MaxItems: 1,
+	})
+	if iter.Next() {
+		return iter.Item(), nil
+	}
+	return nil, iter.Err()
+}
+
+// FindUser searches for matching users with the given email address.
+//
+// If the user cannot be found, no error will be returned but the user object will be nil.
+func (s *S1Client) FindUser(email string) (*S1User, errorx.Error) {
+	logger := s.logger().With().Str("email_address", email).Logger()
+	logger.Debug().Msg("searching for user")
+
+	// search for the user
+	// -- this should never return more than 1 user as e-mail addresses must be unique
+	iter := s.ListUsers(&S1ListOptions{
+		Filters:  map[string]string{"email": email},
+		MaxItems: 1,
+	})
+	if iter.Next() {
+		return iter.Item(), nil
+	}
+	return nil, iter.Err()
+}
+
+// FindUserByID searches for the user with the given ID.
+//
+// If the user cannot be found, no error will be returned but the user object will be nil.
+func (s *S1Client) FindUserByID(id string) (*S1User, errorx.Error) {
+	logger := s.logger().With().Str("user_id", id).Logger()
+	logger.Debug().Msg("searching for user")
+
+	iter := s.ListUsers(&S1ListOptions{
+		Filters:  map[string]string{"ids": id},
+		MaxItems: 1,
+	})
+	if iter.Next() {
+		return iter.Item(), nil
+	}
+	return nil, iter.Err()
+}
+
+// GenerateAPIToken generates a new API token for the user who owns the API token being used.
+//
+// Once the new token has been generated, the token being used by this client is revoked and can no longer be used.
+func (s *S1Client) GenerateAPIToken() (*S1APIToken, errorx.Error) {
+	logger := s.logger()
+	logger.Debug().Msg("generating new API token")
+
+	body := map[string]any{
+		"data": map[string]any{},
+	}
+	resp, errx := s.exec(http.MethodPost, "/users/generate-api-token", withRequestBody(body))
+	if errx != nil {
+		return nil, errx
 	}
 
-	// parse the data
-	var apiRoles []S1APIRoleObject
-	if err := json.Unmarshal(resp.Data, &apiRoles); err != nil {
+	// parse the response
+	var o S1APITokenObject
+	if err := json.Unmarshal(resp.Data, &o); err != nil {
 		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
 		logger.Error().Err(errx).Msg(errx.Error())
 		return nil, errx
 	}
+	if o.Token == "" {
+		errx := errors.NewS1ClientError("failed to generate API token", goerrors.New("server did not return a token"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	expiresAt, err := time.Parse(time.RFC3339, o.ExpiresAt)
+	if err != nil {
+		errx := errors.NewS1ClientError("failed to parse API token expiration date", err)
+		logger.Error().Err(errx).Str("expires_at", o.ExpiresAt).Msg(errx.Error())
+		return nil, errx
+	}
+	return &S1APIToken{
+		Token:     o.Token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
 
-	// convert the response object
-	if len(apiRoles) == 0 {
-		return nil, nil
+// GetAPITokenDetails returns when the API token being used was created and when it expires.
+func (s *S1Client) GetAPITokenDetails() (*S1APITokenDetails, errorx.Error) {
+	logger := s.logger()
+	logger.Debug().Msg("retrieving API token details")
+
+	body := map[string]any{
+		"data": map[string]any{
+			"apiToken": s.apiKey,
+		},
+	}
+	resp, errx := s.exec(http.MethodPost, "/users/api-token-details", withRequestBody(body))
+	if errx != nil {
+		return nil, errx
 	}
-	return s.fromS1APIRoleObject(apiRoles[0])
+
+	// parse the response
+	var o S1APITokenDetailsObject
+	if err := json.Unmarshal(resp.Data, &o); err != nil {
+		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	createdAt, err := time.Parse(time.RFC3339, o.CreatedAt)
+	if err != nil {
+		errx := errors.NewS1ClientError("failed to parse API token creation date", err)
+		logger.Error().Err(errx).Str("created_at", o.CreatedAt).Msg(errx.Error())
+		return nil, errx
+	}
+	expiresAt, err := time.Parse(time.RFC3339, o.ExpiresAt)
+	if err != nil {
+		errx := errors.NewS1ClientError("failed to parse API token expiration date", err)
+		logger.Error().Err(errx).Str("expires_at", o.ExpiresAt).Msg(errx.Error())
+		return nil, errx
+	}
+	return &S1APITokenDetails{
+		CreatedAt: createdAt,
+		ExpiresAt: expiresAt,
+	}, nil
 }
 
-// FindUser searches for matching users with the given email address.
+// GetCurrentUser returns the user who owns the API key being used to access the S1 API.
 //
-// If the user cannot be found, no error will be returned but the user object will be nil.
-func (s *S1Client) FindUser(email string) (*S1User, errorx.Error) {
-	logger := s.appState.Logger().With().Str("email_address", email).Logger()
-	logger.Debug().Msg("searching for user")
+// This is a read-only call which makes it useful for checking that the tenant URL and API key are valid.
+func (s *S1Client) GetCurrentUser() (*S1User, errorx.Error) {
+	logger := s.logger()
+	logger.Debug().Msg("retrieving current user")
 
-	// search for the user
-	// -- this should never return more than 1 user as e-mail addresses must be unique
-	resp, err := s.exec(http.MethodGet, "/users", withRequestParams(map[string]string{
-		"email": email,
-		"limit": "1",
-	}))
+	resp, err := s.exec(http.MethodGet, "/user")
 	if err != nil {
 		return nil, err
 	}
 
-	// parse the data
-	var apiUsers []S1APIUserObject
-	if err := json.Unmarshal(resp.Data, &apiUsers); err != nil {
+	// parse the response
+	var user S1APIUserObject
+	if err := json.Unmarshal(resp.Data, &user); err != nil {
 		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
 		logger.Error().Err(errx).Msg(errx.Error())
 		return nil, errx
 	}
 
 	// convert the response object
-	if len(apiUsers) == 0 {
-		return nil, nil
-	}
-	return s.fromS1APIUserObject(apiUsers[0])
+	return s.fromS1APIUserObject(user)
+}
+
+// ListAccounts returns an iterator over all of the accounts matching the given options.
+//
+// Pages of accounts are requested from the API as the iterator advances. If opts is nil, all accounts are returned.
+func (s *S1Client) ListAccounts(opts *S1ListOptions) *S1Iterator[*S1Account] {
+	return newS1Iterator(s, "/accounts", opts, s.fromS1APIAccountObject)
+}
+
+// ListRoles returns an iterator over all of the roles in the given account matching the given options.
+//
+// Pages of roles are requested from the API as the iterator advances. If opts is nil, all roles are returned.
+func (s *S1Client) ListRoles(accountID string, opts *S1ListOptions) *S1Iterator[*S1Role] {
+	filters := map[string]string{}
+	if opts != nil {
+		for k, v := range opts.Filters {
+			filters[k] = v
+		}
+	} else {
+		opts = &S1ListOptions{}
+	}
+	filters["accountIds"] = accountID
+	return newS1Iterator(s, "/rbac/roles", &S1ListOptions{
+		Filters:  filters,
+		MaxItems: opts.MaxItems,
+		PageSize: opts.PageSize,
+	}, s.fromS1APIRoleObject)
+}
+
+// ListUsers returns an iterator over all of the users matching the given options.
+//
+// Pages of users are requested from the API as the iterator advances. If opts is nil, all users are returned.
+func (s *S1Client) ListUsers(opts *S1ListOptions) *S1Iterator[*S1User] {
+	return newS1Iterator(s, "/users", opts, s.fromS1APIUserObject)
 }
 
 // ReactivateAccount reactivates an expired account and extends its expiration by the configured duration.
 func (s *S1Client) ReactivateAccount(id string, expires time.Time) errorx.Error {
-	logger := s.appState.Logger().With().Str("account_id", id).Logger()
+	logger := s.logger().With().Str("account_id", id).Logger()
 	logger.Info().Msg("reactivating account")
 
 	body := map[string]any{
@@ -383,7 +647,7 @@ func (s *S1Client) ReactivateAccount(id string, expires time.Time) errorx.Error
 
 // ResetUserPassword triggers a password reset email to be sent to the given user.
 func (s *S1Client) ResetUserPassword(userID string) errorx.Error {
-	logger := s.appState.Logger().With().Str("user_id", userID).Logger()
+	logger := s.logger().With().Str("user_id", userID).Logger()
 	logger.Info().Msg("resetting user password")
 
 	body := map[string]any{
@@ -413,9 +677,39 @@ func (s *S1Client) ResetUserPassword(userID string) errorx.Error {
 	return nil
 }
 
+// UpdateAccountExpiration changes when the given active account expires.
+//
+// Expired accounts cannot be updated this way and must be reactivated using ReactivateAccount() instead.
+func (s *S1Client) UpdateAccountExpiration(id string, expires time.Time) (*S1Account, errorx.Error) {
+	logger := s.logger().With().Str("account_id", id).Logger()
+	logger.Info().Time("expires", expires).Msg("updating account expiration")
+
+	body := map[string]any{
+		"data": map[string]any{
+			"expiration":          expires.Format(time.RFC3339),
+			"unlimitedExpiration": false,
+		},
+	}
+	resp, err := s.exec(http.MethodPut, fmt.Sprintf("/accounts/%s", id), withRequestBody(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the response
+	var account S1APIAccountObject
+	if err := json.Unmarshal(resp.Data, &account); err != nil {
+		errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+
+	// convert the response object
+	return s.fromS1APIAccountObject(account)
+}
+
 // UpdateUserScopeRoles updates the scope roles for the given user.
 func (s *S1Client) UpdateUserScopeRoles(userID string, roles []S1UserScopeRole) (*S1User, errorx.Error) {
-	logger := s.appState.Logger().With().Str("user_id", userID).Logger()
+	logger := s.logger().With().Str("user_id", userID).Logger()
 	logger.Debug().Msg("updating scope roles for user")
 
 	body := map[string]any{
@@ -441,72 +735,111 @@ func (s *S1Client) UpdateUserScopeRoles(userID string, roles []S1UserScopeRole)
 	return s.fromS1APIUserObject(user)
 }
 
+// WithLogger returns a copy of the client which writes its log messages using the given logger.
+//
+// The copy shares the HTTP client, rate limiters and retry policy of the original client. This allows callers to add
+// context to log messages (such as the record being processed) without affecting other users of the client.
+func (s *S1Client) WithLogger(logger *zerolog.Logger) *S1Client {
+	cli := *s
+	cli.log = logger
+	return &cli
+}
+
 // exec executes a call to the S1 REST API.
+//
+// Every attempt waits on the client's rate limiters before the request is sent.
+//
+// Requests which fail due to rate limiting are retried after waiting for the time requested by the server. Requests
+// which fail due to network or server errors are retried with exponential backoff, but only if the HTTP method is
+// idempotent.
 func (s *S1Client) exec(method, endpoint string, optFns ...s1ClientExecOptFn) (*S1APIResponse, errorx.Error) {
 	url := fmt.Sprintf("%s/web/api/v2.1%s", s.baseURL, endpoint)
-	logger := s.appState.Logger().With().Str("url", url).Str("method", method).Logger()
+	logger := s.logger().With().Str("url", url).Str("method", method).Logger()
 
-	req := s.client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Accept", "application/json").
-		SetHeader("Authorization", fmt.Sprintf("ApiToken %s", s.apiKey))
-	for _, fn := range optFns {
-		req = fn(req)
+	var resp *resty.Response
+	var err error
+	for attempt := 1; ; attempt++ {
+		if wait := s.rateLimits.wait(endpoint); wait > 0 {
+			logger.Trace().Dur("wait", wait).Msg("request was delayed by rate limiter")
+		}
+		req := s.client.R().
+			SetHeader("Content-Type", "application/json").
+			SetHeader("Accept", "application/json").
+			SetHeader("Authorization", fmt.Sprintf("ApiToken %s", s.apiKey))
+		for _, fn := range optFns {
+			req = fn(req)
+		}
+		resp, err = req.Execute(method, url)
+		wait, retry := s.retryPolicy.retryWait(method, resp, err, attempt)
+		if !retry {
+			break
+		}
+		event := logger.Warn().Int("attempt", attempt).Dur("wait", wait)
+		if err != nil {
+			event = event.Err(err)
+		} else {
+			event = event.Int("status_code", resp.StatusCode())
+		}
+		event.Msg("request failed ; retrying")
+		time.Sleep(wait)
 	}
-	resp, err := req.Execute(method, url)
 	if err != nil {
-		errx := errors.NewS1ClientRequestError(method, url, "failed to execute request", err)
+		errx := errors.NewS1ClientRequestError(method, url, "failed to execute request", transientError{err})
 		logger.Error().Err(errx).Msg(errx.Error())
 		return nil, errx
 	}
 
-	// check response status code
+	// parse the response from the call
+	// -- error responses usually contain details about the error so the body is parsed before checking the status
+	var apiResponse S1APIResponse
+	parseErr := json.Unmarshal(resp.Body(), &apiResponse)
+
+	// check for errors
 	httpCode := resp.StatusCode()
-	if httpCode >= http.StatusMethodNotAllowed {
-		errx := errors.NewS1ClientRequestError(method, url, "failed to execute request",
-			goerrors.New("method is not allowed for endpoint"))
-		logger.Error().Err(errx).Msg(errx.Error())
-		return nil, errx
-	}
-	if httpCode >= http.StatusInternalServerError {
-		errx := errors.NewS1ClientRequestError(method, url, "failed to execute request",
-			fmt.Errorf("request returned server error code %d", httpCode))
+	if httpCode >= http.StatusBadRequest || len(apiResponse.Errors) > 0 {
+		details := []errors.S1APIErrorDetail{}
+		for _, e := range apiResponse.Errors {
+			details = append(details, errors.S1APIErrorDetail{
+				Code:   e.Code,
+				Detail: e.Detail,
+				Title:  e.Title,
+			})
+			logger.Error().Err(goerrors.New(e.Title)).Uint64("error_code", e.Code).Str("detail", e.Detail).
+				Int("status_code", httpCode).Msg(details[len(details)-1].String())
+		}
+		errx := errors.NewS1APIError(method, url, httpCode, details)
 		logger.Error().Err(errx).Int("status_code", httpCode).Msg(errx.Error())
 		return nil, errx
 	}
-
-	// parse the response from the call
-	var apiResponse S1APIResponse
-	if err := json.Unmarshal(resp.Body(), &apiResponse); err != nil {
-		errx := errors.NewS1ClientRequestError(method, url, "failed to unmarshal response from request", err)
+	if parseErr != nil {
+		errx := errors.NewS1ClientRequestError(method, url, "failed to unmarshal response from request", parseErr)
 		logger.Error().Err(errx).Msg(errx.Error())
 		return nil, errx
 	}
+	return &apiResponse, nil
+}
 
-	// check for errors
-	if len(apiResponse.Errors) > 0 {
-		for _, e := range apiResponse.Errors {
-			if e.Detail != "" {
-				logger.Error().Err(fmt.Errorf("%s: %s", e.Title, e.Detail)).Uint64("error_code", e.Code).
-					Msgf("%s: %s", e.Title, e.Detail)
-			} else {
-				logger.Error().Err(goerrors.New(e.Title)).Uint64("error_code", e.Code).Msg(e.Title)
-			}
-		}
-		return nil, errors.NewS1ClientRequestError(method, url, "server returned one or more API errors",
-			goerrors.New("server returned one or more API errors"))
+// logger returns the logger used by the client.
+func (s *S1Client) logger() *zerolog.Logger {
+	if s.log != nil {
+		return s.log
 	}
-	return &apiResponse, nil
+	return s.appState.Logger()
 }
 
 // fromS1APIAccountObject converts an account object returned by the API to an actual S1 account object.
 func (s *S1Client) fromS1APIAccountObject(o S1APIAccountObject) (*S1Account, errorx.Error) {
-	logger := s.appState.Logger()
-	expires, err := time.Parse(time.RFC3339, o.Expiration)
-	if err != nil {
-		errx := errors.NewS1ClientError("failed to parse account expiration date", err)
-		logger.Error().Err(errx).Str("expires", o.Expiration).Msg(errx.Error())
-		return nil, errx
+	logger := s.logger()
+
+	// accounts which never expire do not have an expiration date
+	var expires time.Time
+	if o.Expiration != "" {
+		var err error
+		if expires, err = time.Parse(time.RFC3339, o.Expiration); err != nil {
+			errx := errors.NewS1ClientError("failed to parse account expiration date", err)
+			logger.Error().Err(errx).Str("expires", o.Expiration).Msg(errx.Error())
+			return nil, errx
+		}
 	}
 	return &S1Account{
 		ID:          o.ID,
@@ -531,6 +864,7 @@ func (s *S1Client) fromS1APIUserObject(o S1APIUserObject) (*S1User, errorx.Error
 		ID:              o.ID,
 		EmailAddress:    o.EmailAddress,
 		EmailVerified:   o.EmailVerified,
+		FullName:        o.FullName,
 		TwoFactorStatus: o.TwoFactorStatus,
 		Scope:           o.Scope,
 		ScopeRoles:      []S1UserScopeRole{},
@@ -541,19 +875,28 @@ func (s *S1Client) fromS1APIUserObject(o S1APIUserObject) (*S1User, errorx.Error
 	return user, nil
 }
 
-/*
-// formatAccountName replaces all placeholders in the account name format and returns the result.
-func (s *S1ClientService) formatAccountName(req *ProvisioningRequest) string {
-	// TODO: this can be greatly improved using regexp
-	name := strings.ReplaceAll(s.accountNameFormat, "{request_id}", req.RequestID)
-	name = strings.ReplaceAll(name, "{workshop_id}", req.WorkshopID)
-	name = strings.ReplaceAll(name, "{first_name}", req.FirstName)
-	name = strings.ReplaceAll(name, "{last_name}", req.LastName)
-	name = strings.ReplaceAll(name, "{company}", req.Company)
-	name = strings.ReplaceAll(name, "{title}", req.Title)
-	return strings.ReplaceAll(name, "{address}", req.EmailAddress)
+// isID returns whether or not the value looks like an S1 object ID, which is made up only of digits.
+func isID(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseExpiration converts an account expiration into an actual date and time.
+//
+// The expiration may either be a duration from now (eg: 72h) or an RFC3339 date and time.
+func ParseExpiration(value string) (time.Time, error) {
+	if dur, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(dur), nil
+	}
+	return time.Parse(time.RFC3339, value)
 }
-*/
 
 // s1ClientExecOptFn is used to pass optional settings to the exec() call.
 type s1ClientExecOptFn func(*resty.Request) *resty.Request
@@ -581,7 +924,8 @@ func withRequestParams(params map[string]string) s1ClientExecOptFn {
 
 // s1ClientBuilder is used to configure the S1 client.
 type s1ClientBuilder struct {
-	cli *S1Client
+	cli     *S1Client
+	timeout time.Duration
 }
 
 // NewS1ClientBuilder creates a new s1ClientBuilder object.
@@ -593,12 +937,48 @@ func NewS1ClientBuilder(state *app.State, baseURL, apiKey string) *s1ClientBuild
 			client:   resty.New(),
 			baseURL:  baseURL,
 			apiKey:   apiKey,
+			retryPolicy: s1RetryPolicy{
+				maxRetries: 3,
+				minWait:    1 * time.Second,
+				maxWait:    30 * time.Second,
+			},
 		},
 	}
 }
 
+// NewS1ClientBuilderFromConfig creates a new s1ClientBuilder object using the tenant, API key, rate limit and request
+// settings from the global options.
+//
+// The global options must already have been loaded. If the API key setting refers to where the key is stored, the
+// key is read from there.
+//
+// The following errors are returned by this function:
+// SecretResolveFailure
+func NewS1ClientBuilderFromConfig(state *app.State) (*s1ClientBuilder, errorx.Error) {
+	globalOpts := state.Config().GlobalOptions()
+	apiKey, errx := globalOpts.ResolveAPIKey()
+	if errx != nil {
+		return nil, errx
+	}
+	return NewS1ClientBuilder(state, globalOpts.TenantURL, apiKey).
+		WithRateLimit(globalOpts.RateLimit, globalOpts.RateLimitOverrides).
+		WithRetryPolicy(globalOpts.MaxRetries, globalOpts.RetryWait, globalOpts.RetryMaxWait).
+		WithTimeout(globalOpts.RequestTimeout), nil
+}
+
 // Build finishes the build and returns the configured S1Client object.
+//
+// Requests and responses are written to the log when the log level is trace, with the API token masked.
 func (b *s1ClientBuilder) Build() *S1Client {
+	if b.timeout > 0 {
+		b.cli.client.SetTimeout(b.timeout)
+	}
+	logger := b.cli.logger()
+	b.cli.client.
+		SetLogger(restyLogger{logger: logger}).
+		OnRequestLog(redactRequestLog).
+		OnResponseLog(redactResponseLog).
+		SetDebug(logger.GetLevel() <= zerolog.TraceLevel)
 	return b.cli
 }
 
@@ -609,3 +989,32 @@ func (b *s1ClientBuilder) WithHTTPClient(client *resty.Client) *s1ClientBuilder
 	}
 	return b
 }
+
+// WithRateLimit sets the maximum number of requests per second sent by the client.
+//
+// The overrides map API endpoints (eg: /accounts) to their own limit, which applies to that endpoint and any endpoint
+// beneath it. A rate that is not positive disables limiting.
+func (b *s1ClientBuilder) WithRateLimit(rate float64, overrides map[string]float64) *s1ClientBuilder {
+	b.cli.rateLimits = newS1RateLimiters(rate, overrides)
+	return b
+}
+
+// WithRetryPolicy sets how many times and how long to wait between retries of failed requests.
+func (b *s1ClientBuilder) WithRetryPolicy(maxRetries int, minWait, maxWait time.Duration) *s1ClientBuilder {
+	if maxRetries >= 0 {
+		b.cli.retryPolicy.maxRetries = maxRetries
+	}
+	if minWait >= 0 {
+		b.cli.retryPolicy.minWait = minWait
+	}
+	if maxWait >= minWait {
+		b.cli.retryPolicy.maxWait = maxWait
+	}
+	return b
+}
+
+// WithTimeout sets the maximum amount of time to wait for a single request to complete.
+func (b *s1ClientBuilder) WithTimeout(timeout time.Duration) *s1ClientBuilder {
+	b.timeout = timeout
+	return b
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// Paging defaults.
+const (
+	_DefaultPageSize = 100
+	_MaxPageSize     = 1000
+)
+
+// S1ListOptions controls how results are fetched when listing objects using the S1 API.
+type S1ListOptions struct {
+	// Filters holds additional query parameters used to filter the results.
+	Filters map[string]string
+
+	// MaxItems is the maximum number of items to return in total. A value of 0 means there is no limit.
+	MaxItems uint
+
+	// PageSize is the number of items to request in each call to the API. A value of 0 uses the default page size.
+	PageSize uint
+}
+
+// S1Iterator streams the objects returned by a paginated S1 API list endpoint, following the pagination cursor
+// until all of the results have been returned.
+//
+// Pages are only requested as they are needed, so very large result sets are never held in memory at once:
+//
+//	iter := client.ListAccounts(nil)
+//	for iter.Next() {
+//		account := iter.Item()
+//		...
+//	}
+//	if errx := iter.Err(); errx != nil {
+//		...
+//	}
+type S1Iterator[T any] struct {
+	// unexported variables
+	client   *S1Client
+	convert  func(json.RawMessage) ([]T, errorx.Error)
+	count    uint
+	cursor   string
+	done     bool
+	endpoint string
+	errx     errorx.Error
+	item     T
+	maxItems uint
+	page     []T
+	pageSize uint
+	params   map[string]string
+	total    uint
+}
+
+// newS1Iterator creates a new S1Iterator object which unmarshals each page of results into a list of API objects
+// of type O and converts each one of those into an object of type T.
+func newS1Iterator[O any, T any](client *S1Client, endpoint string, opts *S1ListOptions,
+	convertFn func(O) (T, errorx.Error)) *S1Iterator[T] {
+
+	if opts == nil {
+		opts = &S1ListOptions{}
+	}
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = _DefaultPageSize
+	} else if pageSize > _MaxPageSize {
+		pageSize = _MaxPageSize
+	}
+	params := map[string]string{}
+	for k, v := range opts.Filters {
+		params[k] = v
+	}
+
+	return &S1Iterator[T]{
+		client:   client,
+		endpoint: endpoint,
+		maxItems: opts.MaxItems,
+		pageSize: pageSize,
+		params:   params,
+		convert: func(data json.RawMessage) ([]T, errorx.Error) {
+			var apiObjects []O
+			if err := json.Unmarshal(data, &apiObjects); err != nil {
+				errx := errors.NewS1ClientError("failed to unmarshal response from server", err)
+				client.logger().Error().Err(errx).Str("endpoint", endpoint).Msg(errx.Error())
+				return nil, errx
+			}
+			items := make([]T, 0, len(apiObjects))
+			for _, o := range apiObjects {
+				item, errx := convertFn(o)
+				if errx != nil {
+					return nil, errx
+				}
+				items = append(items, item)
+			}
+			return items, nil
+		},
+	}
+}
+
+// All reads all of the remaining items from the iterator and returns them as a list.
+func (i *S1Iterator[T]) All() ([]T, errorx.Error) {
+	items := []T{}
+	for i.Next() {
+		items = append(items, i.Item())
+	}
+	if i.errx != nil {
+		return nil, i.errx
+	}
+	return items, nil
+}
+
+// Err returns the error, if any, which stopped the iteration.
+func (i *S1Iterator[T]) Err() errorx.Error {
+	return i.errx
+}
+
+// Item returns the current item.
+//
+// This should only be called after a call to Next() returns true.
+func (i *S1Iterator[T]) Item() T {
+	return i.item
+}
+
+// Next advances the iterator to the next item, requesting the next page of results from the API if necessary.
+//
+// It returns false once all items have been returned, the maximum number of items has been reached or an error
+// occurs. Use Err() to tell the difference.
+func (i *S1Iterator[T]) Next() bool {
+	if i.errx != nil || (i.maxItems > 0 && i.count >= i.maxItems) {
+		return false
+	}
+	for len(i.page) == 0 {
+		if i.done {
+			return false
+		}
+		if errx := i.fetch(); errx != nil {
+			i.errx = errx
+			return false
+		}
+	}
+	i.item = i.page[0]
+	i.page = i.page[1:]
+	i.count++
+	return true
+}
+
+// TotalItems returns the total number of items matching the request as reported by the API.
+//
+// The value is only available once the first page of results has been retrieved.
+func (i *S1Iterator[T]) TotalItems() uint {
+	return i.total
+}
+
+// fetch retrieves the next page of results from the API.
+func (i *S1Iterator[T]) fetch() errorx.Error {
+	limit := i.pageSize
+	if i.maxItems > 0 && i.maxItems-i.count < limit {
+		limit = i.maxItems - i.count
+	}
+	params := map[string]string{}
+	for k, v := range i.params {
+		params[k] = v
+	}
+	params["limit"] = strconv.FormatUint(uint64(limit), 10)
+	if i.cursor != "" {
+		params["cursor"] = i.cursor
+	}
+	i.client.logger().Trace().Str("endpoint", i.endpoint).Str("cursor", i.cursor).Uint("limit", limit).
+		Msg("retrieving page of results")
+
+	resp, errx := i.client.exec(http.MethodGet, i.endpoint, withRequestParams(params))
+	if errx != nil {
+		return errx
+	}
+	items, errx := i.convert(resp.Data)
+	if errx != nil {
+		return errx
+	}
+	i.page = items
+	i.cursor = resp.Pagination.NextCursor
+	i.total = resp.Pagination.TotalItems
+	i.done = i.cursor == "" || len(items) == 0
+	return nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// S1LicenseBundle represents a license bundle given to an account.
+type S1LicenseBundle struct {
+	Name     string
+	Surfaces []S1LicenseSurface
+}
+
+// S1LicenseSurface represents the number of a single type of agent licensed as part of a bundle.
+type S1LicenseSurface struct {
+	Name  string
+	Count int
+}
+
+// S1LicenseSetting represents a single license setting for an account.
+type S1LicenseSetting struct {
+	GroupName string
+	Setting   string
+}
+
+// ParseLicenseBundles converts a list of license bundles into the bundles to give an account.
+//
+// Bundles are separated by semicolons. Each bundle is a name optionally followed by a colon and the surfaces of the
+// bundle separated by pipes, where each surface is a name and a count separated by an equals sign. For example:
+//
+//	complete:Total Agents=100|Cloud Workloads=20;control:Total Agents=5
+func ParseLicenseBundles(value string) ([]S1LicenseBundle, error) {
+	bundles := []S1LicenseBundle{}
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, surfaceList, _ := strings.Cut(item, ":")
+		bundle := S1LicenseBundle{
+			Name:     strings.TrimSpace(name),
+			Surfaces: []S1LicenseSurface{},
+		}
+		if bundle.Name == "" {
+			return nil, fmt.Errorf("bundle '%s' does not have a name", item)
+		}
+		for _, surface := range strings.Split(surfaceList, "|") {
+			if surface = strings.TrimSpace(surface); surface == "" {
+				continue
+			}
+			surfaceName, countValue, found := strings.Cut(surface, "=")
+			count, err := strconv.Atoi(strings.TrimSpace(countValue))
+			if !found || strings.TrimSpace(surfaceName) == "" || err != nil || count <= 0 {
+				return nil, fmt.Errorf("surface '%s' of bundle '%s' must be a name and a count greater than 0 "+
+					"separated by '='", surface, bundle.Name)
+			}
+			bundle.Surfaces = append(bundle.Surfaces, S1LicenseSurface{
+				Name:  strings.TrimSpace(surfaceName),
+				Count: count,
+			})
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// ParseLicenseSettings converts a list of license settings into a map of group names to settings.
+//
+// Settings are separated by semicolons and each setting is a group name and a value separated by an equals sign. For
+// example:
+//
+//	dv_retention=90 Days;remote_shell_availability=Disabled
+func ParseLicenseSettings(value string) (map[string]string, error) {
+	settings := map[string]string{}
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		group, setting, found := strings.Cut(item, "=")
+		group = strings.TrimSpace(group)
+		setting = strings.TrimSpace(setting)
+		if !found || group == "" || setting == "" {
+			return nil, fmt.Errorf("license setting '%s' must be a group name and a setting separated by '='", item)
+		}
+		settings[group] = setting
+	}
+	return settings, nil
+}
+
+// NewLicenseSettings converts a map of group names to settings into a list of license settings.
+//
+// The settings are sorted by group name so that requests are always built the same way.
+func NewLicenseSettings(settings map[string]string) []S1LicenseSetting {
+	groups := make([]string, 0, len(settings))
+	for group := range settings {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	list := make([]S1LicenseSetting, 0, len(groups))
+	for _, group := range groups {
+		list = append(list, S1LicenseSetting{
+			GroupName: group,
+			Setting:   settings[group],
+		})
+	}
+	return list
+}
+
+// String returns the bundle in the same form accepted by ParseLicenseBundles().
+func (b S1LicenseBundle) String() string {
+	surfaces := make([]string, 0, len(b.Surfaces))
+	for _, surface := range b.Surfaces {
+		surfaces = append(surfaces, fmt.Sprintf("%s=%d", surface.Name, surface.Count))
+	}
+	if len(surfaces) == 0 {
+		return b.Name
+	}
+	return fmt.Sprintf("%s:%s", b.Name, strings.Join(surfaces, "|"))
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+)
+
+// _RedactedAPIToken replaces the API token in the Authorization header of logged requests.
+const _RedactedAPIToken = "ApiToken <redacted>"
+
+// secretFieldRegexp matches API tokens and passwords sent or received in the body of a request.
+var secretFieldRegexp = regexp.MustCompile(
+	`("(?:apiToken|confirmPassword|password|token)"\s*:\s*")(?:[^"\\]|\\.)*(")`)
+
+// restyLogger writes messages from the REST client to the application log.
+type restyLogger struct {
+	// unexported variables
+	logger *zerolog.Logger
+}
+
+// Debugf writes a debug message to the log.
+//
+// The REST client only writes debug messages when request tracing is enabled, so these are logged at trace level.
+func (l restyLogger) Debugf(format string, v ...any) {
+	l.logger.Trace().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// Errorf writes an error message to the log.
+func (l restyLogger) Errorf(format string, v ...any) {
+	l.logger.Error().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// Warnf writes a warning message to the log.
+func (l restyLogger) Warnf(format string, v ...any) {
+	l.logger.Warn().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// redactRequestLog masks any API tokens and passwords before a request is written to the log.
+//
+// The REST client passes a copy of the request headers and body so the actual request is unaffected.
+func redactRequestLog(rl *resty.RequestLog) error {
+	if rl.Header.Get("Authorization") != "" {
+		rl.Header.Set("Authorization", _RedactedAPIToken)
+	}
+	rl.Body = redactSecretFields(rl.Body)
+	return nil
+}
+
+// redactResponseLog masks any API tokens and passwords before a response is written to the log.
+func redactResponseLog(rl *resty.ResponseLog) error {
+	rl.Body = redactSecretFields(rl.Body)
+	return nil
+}
+
+// redactSecretFields masks the value of any API token or password fields in the given JSON.
+func redactSecretFields(body string) string {
+	return secretFieldRegexp.ReplaceAllString(body, "${1}<redacted>${2}")
+}
@@ -29,16 +29,37 @@ type S1APIPageInfo struct {
 	} `json:"pagination"`
 }
 
+// S1ProvisioningAction identifies what was done in order to provision an object.
+type S1ProvisioningAction string
+
+// Provisioning actions.
+const (
+	// S1ProvisioningActionAdded indicates an existing user was added to the account.
+	S1ProvisioningActionAdded S1ProvisioningAction = "added"
+
+	// S1ProvisioningActionCreated indicates a new object was created.
+	S1ProvisioningActionCreated S1ProvisioningAction = "created"
+
+	// S1ProvisioningActionExisting indicates the object already existed and nothing needed to be changed.
+	S1ProvisioningActionExisting S1ProvisioningAction = "existing"
+
+	// S1ProvisioningActionReactivated indicates an existing expired account was reactivated.
+	S1ProvisioningActionReactivated S1ProvisioningAction = "reactivated"
+)
+
 // S1AccountProvisioningRequest holds the body of an account provisioning request.
 type S1AccountProvisioningRequest struct {
-	AccountName       string   `json:"account_name"`
-	AccountType       string   `json:"account_type"`
-	Expires           string   `json:"expires"`
-	ExternalID        string   `json:"external_id"`
-	ReactivateAccount bool     `json:"reactivate_account"`
-	Bundle            string   `json:"bundle"`
-	TotalAgents       int      `json:"total_agents"`
-	Modules           []string `json:"modules"`
+	AccountName       string             `json:"account_name"`
+	AccountType       string             `json:"account_type"`
+	BillingMode       string             `json:"billing_mode"`
+	Expires           string             `json:"expires"`
+	ExternalID        string             `json:"external_id"`
+	Inherits          bool               `json:"inherits"`
+	ReactivateAccount bool               `json:"reactivate_account"`
+	UsageType         string             `json:"usage_type"`
+	Bundles           []S1LicenseBundle  `json:"bundles"`
+	Modules           []string           `json:"modules"`
+	Settings          []S1LicenseSetting `json:"settings"`
 }
 
 // S1APIAccountObject represents an account object returned by the S1 API.
@@ -53,6 +74,8 @@ type S1APIAccountObject struct {
 }
 
 // S1Account represents the actual S1 account object.
+//
+// The expiration is the zero time for accounts which never expire.
 type S1Account struct {
 	ID          string
 	AccountType string
@@ -64,11 +87,50 @@ type S1Account struct {
 }
 
 // S1UserProvisioningRequest holds the body of a user provisioning request.
+//
+// The role may be either the name or the ID of a role in the account.
 type S1UserProvisioningRequest struct {
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
 	EmailAddress string `json:"email_address"`
 	Role         string `json:"role"`
+
+	// Password is the initial password given to a new user. A random password is generated if it is empty.
+	Password string `json:"-"`
+}
+
+// S1APITokenObject represents a newly generated API token returned by the S1 API.
+type S1APITokenObject struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// S1APIToken represents an actual newly generated S1 API token.
+type S1APIToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// S1APITokenDetailsObject represents the details of an API token returned by the S1 API.
+type S1APITokenDetailsObject struct {
+	CreatedAt string `json:"createdAt"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// S1APITokenDetails represents the actual details of an S1 API token.
+type S1APITokenDetails struct {
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ExpiresWithin returns whether or not the token expires within the given amount of time from now.
+func (d *S1APITokenDetails) ExpiresWithin(window time.Duration) bool {
+	return time.Until(d.ExpiresAt) <= window
+}
+
+// IsExpired returns whether or not the token has already expired.
+func (d *S1APITokenDetails) IsExpired() bool {
+	return !d.ExpiresAt.After(time.Now())
 }
 
 // S1APIUserObject represents a user object returned by the S1 API.
@@ -76,6 +138,7 @@ type S1APIUserObject struct {
 	ID              string                     `json:"id"`
 	EmailAddress    string                     `json:"email"`
 	EmailVerified   bool                       `json:"emailVerified"`
+	FullName        string                     `json:"fullName"`
 	TwoFactorStatus string                     `json:"twoFaStatus"`
 	Scope           string                     `json:"scope"`
 	ScopeRoles      []S1APIUserScopeRoleObject `json:"scopeRoles"`
@@ -93,6 +156,7 @@ type S1User struct {
 	ID              string
 	EmailAddress    string
 	EmailVerified   bool
+	FullName        string
 	TwoFactorStatus string
 	Scope           string
 	ScopeRoles      []S1UserScopeRole
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"crypto/rand"
+	goerrors "errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// passwordClasses holds the characters belonging to each class of character which may be required in a password.
+var passwordClasses = map[string]string{
+	"digit":  "0123456789",
+	"lower":  "abcdefghijklmnopqrstuvwxyz",
+	"symbol": "!#$%&*+-=?@^_~",
+	"upper":  "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+}
+
+// S1PasswordPolicy describes the initial passwords generated for new users.
+type S1PasswordPolicy struct {
+	// Length is the number of characters in the password.
+	Length int
+
+	// Classes are the classes of characters the password is built from. At least one character from each class is
+	// always included.
+	Classes []string
+}
+
+// DefaultPasswordPolicy returns the policy used when no other policy is given.
+func DefaultPasswordPolicy() S1PasswordPolicy {
+	return S1PasswordPolicy{
+		Length:  32,
+		Classes: []string{"lower", "upper", "digit", "symbol"},
+	}
+}
+
+// NewPasswordPolicy creates a policy from a length and a comma-separated list of character classes.
+//
+// The supported classes are lower, upper, digit and symbol.
+func NewPasswordPolicy(length int, classes string) (S1PasswordPolicy, error) {
+	policy := S1PasswordPolicy{
+		Length:  length,
+		Classes: []string{},
+	}
+	for _, class := range strings.Split(classes, ",") {
+		if class = strings.ToLower(strings.TrimSpace(class)); class == "" {
+			continue
+		}
+		if _, ok := passwordClasses[class]; !ok {
+			return policy, fmt.Errorf("'%s' is not a character class; use lower, upper, digit or symbol", class)
+		}
+		policy.Classes = append(policy.Classes, class)
+	}
+	if len(policy.Classes) == 0 {
+		return policy, goerrors.New("at least one character class is required")
+	}
+	if length < len(policy.Classes) {
+		return policy, fmt.Errorf("password length must be at least %d to include every character class",
+			len(policy.Classes))
+	}
+	return policy, nil
+}
+
+// Generate creates a new random password which satisfies the policy.
+//
+// The password is generated using a cryptographically secure random number generator.
+func (p S1PasswordPolicy) Generate() (string, error) {
+	if len(p.Classes) == 0 || p.Length < len(p.Classes) {
+		return "", goerrors.New("password policy cannot be satisfied")
+	}
+
+	// include one character from each class and fill the rest from every class
+	all := ""
+	passwd := make([]byte, 0, p.Length)
+	for _, class := range p.Classes {
+		chars := passwordClasses[class]
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		passwd = append(passwd, c)
+		all += chars
+	}
+	for len(passwd) < p.Length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		passwd = append(passwd, c)
+	}
+
+	// shuffle so the required characters are not always first
+	for i := len(passwd) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		passwd[i], passwd[j.Int64()] = passwd[j.Int64()], passwd[i]
+	}
+	return string(passwd), nil
+}
+
+// randomChar returns a random character from the given characters.
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n.Int64()], nil
+}
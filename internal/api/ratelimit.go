@@ -0,0 +1,114 @@
+package api
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// s1RateLimiter is a token bucket used to limit how quickly requests are sent to the S1 API.
+//
+// The bucket holds up to one second's worth of tokens and is refilled continuously at the configured rate. Each
+// request takes a token, waiting for the bucket to refill if it is empty. It is safe to share a single limiter
+// between multiple goroutines.
+type s1RateLimiter struct {
+	// unexported variables
+	burst  float64
+	last   time.Time
+	mutex  sync.Mutex
+	rate   float64
+	tokens float64
+}
+
+// newS1RateLimiter creates a new s1RateLimiter object which allows the given number of requests per second.
+//
+// If rate is not positive, nil is returned which means requests are not limited.
+func newS1RateLimiter(rate float64) *s1RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	burst := math.Max(1, math.Ceil(rate))
+	return &s1RateLimiter{
+		burst:  burst,
+		last:   time.Now(),
+		rate:   rate,
+		tokens: burst,
+	}
+}
+
+// wait blocks until the caller is allowed to send a request.
+//
+// Tokens are reserved in the order callers arrive, so a burst of concurrent callers is spread out evenly over time
+// rather than all of them waking at once.
+func (l *s1RateLimiter) wait() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mutex.Unlock()
+
+	time.Sleep(wait)
+	return wait
+}
+
+// s1RateLimiters holds the shared rate limiter for all requests along with any endpoint-specific limiters.
+type s1RateLimiters struct {
+	// unexported variables
+	endpoints map[string]*s1RateLimiter
+	shared    *s1RateLimiter
+}
+
+// newS1RateLimiters creates a new s1RateLimiters object.
+//
+// The overrides map API endpoints (eg: /accounts) to the number of requests per second allowed for that endpoint and
+// any endpoint beneath it. Requests to those endpoints wait on their own limiter as well as the shared one, so an
+// override can only make an endpoint slower than the shared limit.
+func newS1RateLimiters(rate float64, overrides map[string]float64) *s1RateLimiters {
+	limiters := &s1RateLimiters{
+		endpoints: map[string]*s1RateLimiter{},
+		shared:    newS1RateLimiter(rate),
+	}
+	for endpoint, endpointRate := range overrides {
+		limiters.endpoints["/"+strings.Trim(endpoint, "/")] = newS1RateLimiter(endpointRate)
+	}
+	return limiters
+}
+
+// forEndpoint returns the endpoint-specific limiter that applies to the given endpoint.
+//
+// The override with the longest matching endpoint path wins. If no override matches, nil is returned.
+func (l *s1RateLimiters) forEndpoint(endpoint string) *s1RateLimiter {
+	if l == nil {
+		return nil
+	}
+	match := ""
+	var limiter *s1RateLimiter
+	for prefix, endpointLimiter := range l.endpoints {
+		if (endpoint == prefix || strings.HasPrefix(endpoint, prefix+"/")) && len(prefix) > len(match) {
+			match = prefix
+			limiter = endpointLimiter
+		}
+	}
+	return limiter
+}
+
+// wait blocks until the caller is allowed to send a request to the given endpoint.
+//
+// The endpoint-specific limiter, if any, is waited on first so that a token is only taken from the shared limiter
+// once the request is about to be sent. The total time spent waiting is returned.
+func (l *s1RateLimiters) wait(endpoint string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	wait := l.forEndpoint(endpoint).wait()
+	return wait + l.shared.wait()
+}
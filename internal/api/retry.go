@@ -0,0 +1,117 @@
+package api
+
+import (
+	goerrors "errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// s1RetryPolicy controls how failed requests to the S1 API are retried.
+type s1RetryPolicy struct {
+	maxRetries int
+	maxWait    time.Duration
+	minWait    time.Duration
+}
+
+// backoff returns how long to wait before making the given retry attempt (starting at 1).
+//
+// The wait time doubles with each attempt up to the maximum wait time and includes random jitter so that
+// concurrent requests do not all retry at the same moment. If the server told us how long to wait using the
+// Retry-After header, that value is used instead.
+func (p s1RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	wait := p.minWait
+	for i := 1; i < attempt && wait < p.maxWait; i++ {
+		wait *= 2
+	}
+	if wait > p.maxWait {
+		wait = p.maxWait
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	// wait somewhere between half and all of the calculated time
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryWait determines whether or not the result of the given attempt (starting at 1) should be retried and, if
+// so, how long to wait before trying again.
+//
+// Rate limited requests are always retried since the server rejected them without processing them. Network
+// failures and server errors are only retried for idempotent methods since the request may have been processed
+// before the failure occurred.
+func (p s1RetryPolicy) retryWait(method string, resp *resty.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt > p.maxRetries {
+		return 0, false
+	}
+	if err != nil {
+		return p.backoff(attempt, 0), isIdempotentMethod(method)
+	}
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		return p.backoff(attempt, parseRetryAfter(resp.Header().Get("Retry-After"))), true
+	}
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		return p.backoff(attempt, 0), isIdempotentMethod(method)
+	}
+	return 0, false
+}
+
+// transientError marks a failure which may succeed if the request is attempted again.
+type transientError struct {
+	error
+}
+
+// Unwrap returns the underlying error.
+func (e transientError) Unwrap() error {
+	return e.error
+}
+
+// isIdempotentMethod returns whether or not repeating a request with the given HTTP method has the same effect as
+// making the request once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isTransientError returns whether or not the given error was caused by a transient network or server failure.
+func isTransientError(errx errorx.Error) bool {
+	if errx == nil {
+		return false
+	}
+	if e, ok := errx.(*errors.S1APIError); ok {
+		return e.IsServerError()
+	}
+	var e transientError
+	return goerrors.As(errx.InternalError(), &e)
+}
+
+// parseRetryAfter converts the value of a Retry-After header into a duration.
+//
+// The header may either contain a number of seconds or an HTTP date. If the value cannot be parsed, 0 is returned.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -52,17 +52,4 @@ func (w *ProvisioningWorkflow) ResetUserPassword(req *ProvisioningRequest) *Erro
 	}
 	return w.s1Client.ResetUserPassword(user.ID)
 }
-
-// ValidateRequest ensures the request body passed in is valid.
-func (w *ProvisioningWorkflow) ValidateRequest(req *ProvisioningRequest) *Error {
-	if _, err := mail.ParseAddress(req.EmailAddress); err != nil {
-		ErrorMsg(req, "email address '%s' is invalid: %s", req.EmailAddress, err.Error())
-		return NewError(ERR_VALIDATE_REQUEST, err)
-	}
-	if req.FirstName == "" || req.LastName == "" || req.Company == "" || req.Title == "" {
-		ErrorMsg(req, "one or more required request parameters is empty")
-		return NewError(ERR_VALIDATE_REQUEST, errors.New("missing required request parameters"))
-	}
-	return nil
-}
 */
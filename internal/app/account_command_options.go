@@ -0,0 +1,161 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+)
+
+// accountCommandOptions holds options for the 'account' subcommand and its subcommands.
+type accountCommandOptions struct {
+	// unexported variables
+	appState                        *State
+	parent                          *commandOptions
+	configKey                       string
+	isLoaded                        bool
+	accountExpireCommandOptions     *deprovisionCommandOptions
+	accountExpireCommandOptionsOnce *sync.Once
+	accountExtendCommandOptions     *accountExtendCommandOptions
+	accountExtendCommandOptionsOnce *sync.Once
+	accountListCommandOptions       *accountListCommandOptions
+	accountListCommandOptionsOnce   *sync.Once
+}
+
+// jsonAccountCommandOptions is just an alias for accountCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonAccountCommandOptions accountCommandOptions
+
+// newAccountCommandOptions returns a new object with defaults set.
+func newAccountCommandOptions(state *State, parent *commandOptions) *accountCommandOptions {
+	configKey := _ConfigCommandAccountKey
+
+	return &accountCommandOptions{
+		appState:                        state,
+		parent:                          parent,
+		configKey:                       configKey,
+		accountExpireCommandOptionsOnce: &sync.Once{},
+		accountExtendCommandOptionsOnce: &sync.Once{},
+		accountListCommandOptionsOnce:   &sync.Once{},
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *accountCommandOptions) BindFlags(cmd *cobra.Command) {
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *accountCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *accountCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *accountCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *accountCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'account' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *accountCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonAccountCommandOptions(*c)
+	//lint:ignore SA9005 this function may change in the future to export fields
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *accountCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *accountCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// Expire returns the options for the "account expire" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *accountCommandOptions) Expire() *deprovisionCommandOptions {
+	c.accountExpireCommandOptionsOnce.Do(func() {
+		c.accountExpireCommandOptions = newDeprovisionCommandOptions(c.appState, c, _ConfigCommandAccountExpireKey,
+			func() viperDeprovisionCommandOptions {
+				return c.appState.config.viperConfig.CommandOptions.Account.Expire
+			})
+	})
+	return c.accountExpireCommandOptions
+}
+
+// Extend returns the options for the "account extend" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *accountCommandOptions) Extend() *accountExtendCommandOptions {
+	c.accountExtendCommandOptionsOnce.Do(func() {
+		c.accountExtendCommandOptions = newAccountExtendCommandOptions(c.appState, c)
+	})
+	return c.accountExtendCommandOptions
+}
+
+// List returns the options for the "account list" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *accountCommandOptions) List() *accountListCommandOptions {
+	c.accountListCommandOptionsOnce.Do(func() {
+		c.accountListCommandOptions = newAccountListCommandOptions(c.appState, c)
+	})
+	return c.accountListCommandOptions
+}
+
+// viperAccountCommandOptions holds the options for any 'account' subcommands.
+type viperAccountCommandOptions struct {
+	Expire viperDeprovisionCommandOptions   `mapstructure:"expire"`
+	Extend viperAccountExtendCommandOptions `mapstructure:"extend"`
+	List   viperAccountListCommandOptions   `mapstructure:"list"`
+}
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// accountExtendCommandOptions holds options for the 'account extend' subcommand.
+type accountExtendCommandOptions struct {
+	AccountIDs   string `json:"account_ids"`
+	AccountNames string `json:"account_names"`
+	AssumeYes    bool   `json:"assume_yes"`
+	CSVSeparator string `json:"csv_separator"`
+	CSVSource    string `json:"csv_source"`
+	DryRun       bool   `json:"dry_run"`
+	Expires      string `json:"expires"`
+	ExternalIDs  string `json:"external_ids"`
+
+	// unexported variables
+	appState  *State
+	parent    *accountCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonAccountExtendCommandOptions is just an alias for accountExtendCommandOptions that is used during marshalling
+// and unmarshalling to prevent infinite recursion.
+type jsonAccountExtendCommandOptions accountExtendCommandOptions
+
+// newAccountExtendCommandOptions returns a new object with defaults set.
+func newAccountExtendCommandOptions(state *State, parent *accountCommandOptions) *accountExtendCommandOptions {
+	configKey := _ConfigCommandAccountExtendKey
+	viper.SetDefault(fmt.Sprintf("%s.account_ids", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_names", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.assume_yes", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.csv_separator", configKey), _DefaultCSVSeparator)
+	viper.SetDefault(fmt.Sprintf("%s.csv_source", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.dry_run", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.expires", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.external_ids", configKey), "")
+
+	return &accountExtendCommandOptions{
+		CSVSeparator: _DefaultCSVSeparator,
+		appState:     state,
+		parent:       parent,
+		configKey:    configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *accountExtendCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-id
+	flags.String("account-id", "", "comma-separated list of IDs of the accounts to extend")
+	viper.BindPFlag(fmt.Sprintf("%s.account_ids", c.configKey), flags.Lookup("account-id"))
+	viper.BindEnv(fmt.Sprintf("%s.account_ids", c.configKey), fmt.Sprintf("%sACCOUNT_IDS", envPrefix))
+
+	// --account-name
+	flags.String("account-name", "", "comma-separated list of names of the accounts to extend")
+	viper.BindPFlag(fmt.Sprintf("%s.account_names", c.configKey), flags.Lookup("account-name"))
+	viper.BindEnv(fmt.Sprintf("%s.account_names", c.configKey), fmt.Sprintf("%sACCOUNT_NAMES", envPrefix))
+
+	// --csv-separator
+	flags.String("csv-separator", _DefaultCSVSeparator, "when using a CSV, this is the separator token")
+	viper.BindPFlag(fmt.Sprintf("%s.csv_separator", c.configKey), flags.Lookup("csv-separator"))
+	viper.BindEnv(fmt.Sprintf("%s.csv_separator", c.configKey), fmt.Sprintf("%sCSV_SEPARATOR", envPrefix))
+
+	// --csv-source
+	flags.String("csv-source", "", "extend the accounts listed in the given CSV file")
+	viper.BindPFlag(fmt.Sprintf("%s.csv_source", c.configKey), flags.Lookup("csv-source"))
+	viper.BindEnv(fmt.Sprintf("%s.csv_source", c.configKey), fmt.Sprintf("%sCSV_SOURCE", envPrefix))
+
+	// --dry-run
+	flags.Bool("dry-run", false, "show what would be extended without making any changes")
+	viper.BindPFlag(fmt.Sprintf("%s.dry_run", c.configKey), flags.Lookup("dry-run"))
+	viper.BindEnv(fmt.Sprintf("%s.dry_run", c.configKey), fmt.Sprintf("%sDRY_RUN", envPrefix))
+
+	// --expires
+	flags.String("expires", "", "new expiration for the accounts as a duration (eg: 72h) or RFC3339 date")
+	viper.BindPFlag(fmt.Sprintf("%s.expires", c.configKey), flags.Lookup("expires"))
+	viper.BindEnv(fmt.Sprintf("%s.expires", c.configKey), fmt.Sprintf("%sEXPIRES", envPrefix))
+
+	// --external-id
+	flags.String("external-id", "", "comma-separated list of external IDs of the accounts to extend")
+	viper.BindPFlag(fmt.Sprintf("%s.external_ids", c.configKey), flags.Lookup("external-id"))
+	viper.BindEnv(fmt.Sprintf("%s.external_ids", c.configKey), fmt.Sprintf("%sEXTERNAL_IDS", envPrefix))
+
+	// --yes
+	flags.BoolP("yes", "y", false, "extend the accounts without asking for confirmation")
+	viper.BindPFlag(fmt.Sprintf("%s.assume_yes", c.configKey), flags.Lookup("yes"))
+	viper.BindEnv(fmt.Sprintf("%s.assume_yes", c.configKey), fmt.Sprintf("%sASSUME_YES", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *accountExtendCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *accountExtendCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *accountExtendCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.Account.Extend
+	logger := c.appState.logger
+
+	// at least one account must be given
+	if viperConfig.AccountIDs == "" && viperConfig.AccountNames == "" && viperConfig.ExternalIDs == "" &&
+		viperConfig.CSVSource == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_source",
+			viperConfig.CSVSource, goerrors.New("an account ID, account name, external ID or CSV file is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "csv_source").
+			Str("value", viperConfig.CSVSource).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// using a CSV file
+	if viperConfig.CSVSource != "" {
+		// CSV separator cannot be empty
+		if viperConfig.CSVSeparator == "" {
+			viperConfig.CSVSeparator = _DefaultCSVSeparator
+			logger.Warn().Msgf("an empty CSV separator is not allowed ; defaulting to %s for separator",
+				_DefaultCSVSeparator)
+		}
+
+		// special TAB case
+		if strings.EqualFold(viperConfig.CSVSeparator, "tab") {
+			viperConfig.CSVSeparator = "\t"
+		}
+
+		// CSV separator should be a single character
+		if len(viperConfig.CSVSeparator) != 1 {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_separator",
+				viperConfig.CSVSeparator, goerrors.New("CSV separator must be a single character"))
+			logger.Error().
+				Err(errx).
+				Str("option", "csv_separator").
+				Str("value", viperConfig.CSVSeparator).
+				Msg(errx.Error())
+			return errx
+		}
+
+		// make sure CSV file exists
+		_, err := os.Stat(viperConfig.CSVSource)
+		if err != nil {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_source",
+				viperConfig.CSVSource, err)
+			logger.Error().
+				Err(errx).
+				Str("option", "csv_source").
+				Str("value", viperConfig.CSVSource).
+				Msg(errx.Error())
+			return errx
+		}
+	} else if viperConfig.Expires == "" {
+		// without a CSV file there is nowhere else for the new expiration to come from
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "expires",
+			viperConfig.Expires, goerrors.New("a new expiration is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "expires").
+			Str("value", viperConfig.Expires).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// save options
+	c.AccountIDs = viperConfig.AccountIDs
+	c.AccountNames = viperConfig.AccountNames
+	c.AssumeYes = viperConfig.AssumeYes
+	c.CSVSeparator = viperConfig.CSVSeparator
+	c.CSVSource = viperConfig.CSVSource
+	c.DryRun = viperConfig.DryRun
+	c.Expires = viperConfig.Expires
+	c.ExternalIDs = viperConfig.ExternalIDs
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *accountExtendCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'account extend' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *accountExtendCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonAccountExtendCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *accountExtendCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *accountExtendCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperAccountExtendCommandOptions holds the options for the 'account extend' subcommand.
+type viperAccountExtendCommandOptions struct {
+	AccountIDs   string `mapstructure:"account_ids"`
+	AccountNames string `mapstructure:"account_names"`
+	AssumeYes    bool   `mapstructure:"assume_yes"`
+	CSVSeparator string `mapstructure:"csv_separator"`
+	CSVSource    string `mapstructure:"csv_source"`
+	DryRun       bool   `mapstructure:"dry_run"`
+	Expires      string `mapstructure:"expires"`
+	ExternalIDs  string `mapstructure:"external_ids"`
+}
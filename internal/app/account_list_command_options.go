@@ -0,0 +1,202 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// accountListCommandOptions holds options for the 'account list' subcommand.
+type accountListCommandOptions struct {
+	AccountType    string `json:"account_type"`
+	ExpiringBefore string `json:"expiring_before"`
+	ExternalID     string `json:"external_id"`
+	Name           string `json:"name"`
+	States         string `json:"states"`
+
+	// unexported variables
+	appState  *State
+	parent    *accountCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonAccountListCommandOptions is just an alias for accountListCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonAccountListCommandOptions accountListCommandOptions
+
+// newAccountListCommandOptions returns a new object with defaults set.
+func newAccountListCommandOptions(state *State, parent *accountCommandOptions) *accountListCommandOptions {
+	configKey := _ConfigCommandAccountListKey
+	viper.SetDefault(fmt.Sprintf("%s.account_type", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.expiring_before", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.external_id", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.states", configKey), "")
+
+	return &accountListCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *accountListCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-type
+	flags.String("account-type", "", "only list accounts of this type: Trial or Paid")
+	viper.BindPFlag(fmt.Sprintf("%s.account_type", c.configKey), flags.Lookup("account-type"))
+	viper.BindEnv(fmt.Sprintf("%s.account_type", c.configKey), fmt.Sprintf("%sACCOUNT_TYPE", envPrefix))
+
+	// --expiring-before
+	flags.String("expiring-before", "",
+		"only list accounts expiring before this RFC3339 date and time or duration from now (eg: 720h)")
+	viper.BindPFlag(fmt.Sprintf("%s.expiring_before", c.configKey), flags.Lookup("expiring-before"))
+	viper.BindEnv(fmt.Sprintf("%s.expiring_before", c.configKey), fmt.Sprintf("%sEXPIRING_BEFORE", envPrefix))
+
+	// --external-id
+	flags.String("external-id", "", "only list accounts with this external ID")
+	viper.BindPFlag(fmt.Sprintf("%s.external_id", c.configKey), flags.Lookup("external-id"))
+	viper.BindEnv(fmt.Sprintf("%s.external_id", c.configKey), fmt.Sprintf("%sEXTERNAL_ID", envPrefix))
+
+	// --name
+	flags.String("name", "", "only list accounts whose name contains this text")
+	viper.BindPFlag(fmt.Sprintf("%s.name", c.configKey), flags.Lookup("name"))
+	viper.BindEnv(fmt.Sprintf("%s.name", c.configKey), fmt.Sprintf("%sNAME", envPrefix))
+
+	// --state
+	flags.String("state", "", "only list accounts in these comma-separated states: active, expired, deleted")
+	viper.BindPFlag(fmt.Sprintf("%s.states", c.configKey), flags.Lookup("state"))
+	viper.BindEnv(fmt.Sprintf("%s.states", c.configKey), fmt.Sprintf("%sSTATES", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *accountListCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *accountListCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *accountListCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.Account.List
+	logger := c.appState.logger
+
+	// account type must be one supported by the S1 API
+	if viperConfig.AccountType != "" {
+		accountType, err := ParseAccountType(viperConfig.AccountType)
+		if err != nil {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "account_type",
+				viperConfig.AccountType, err)
+			logger.Error().
+				Err(errx).
+				Str("option", "account_type").
+				Str("value", viperConfig.AccountType).
+				Msg(errx.Error())
+			return errx
+		}
+		viperConfig.AccountType = accountType
+	}
+
+	// states must be ones supported by the S1 API
+	states := []string{}
+	for _, state := range strings.Split(viperConfig.States, ",") {
+		state = strings.ToLower(strings.TrimSpace(state))
+		if state == "" {
+			continue
+		}
+		if state != "active" && state != "expired" && state != "deleted" {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "states",
+				viperConfig.States, fmt.Errorf("'%s' is not a valid state; must be one of: active, expired, deleted",
+					state))
+			logger.Error().
+				Err(errx).
+				Str("option", "states").
+				Str("value", viperConfig.States).
+				Msg(errx.Error())
+			return errx
+		}
+		states = append(states, state)
+	}
+
+	// save options
+	c.AccountType = viperConfig.AccountType
+	c.ExpiringBefore = viperConfig.ExpiringBefore
+	c.ExternalID = viperConfig.ExternalID
+	c.Name = viperConfig.Name
+	c.States = strings.Join(states, ",")
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *accountListCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'account list' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *accountListCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonAccountListCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *accountListCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *accountListCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperAccountListCommandOptions holds the options for the 'account list' subcommand.
+type viperAccountListCommandOptions struct {
+	AccountType    string `mapstructure:"account_type"`
+	ExpiringBefore string `mapstructure:"expiring_before"`
+	ExternalID     string `mapstructure:"external_id"`
+	Name           string `mapstructure:"name"`
+	States         string `mapstructure:"states"`
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+)
+
+// authRotateCommandOptions holds options for the 'auth rotate' subcommand.
+type authRotateCommandOptions struct {
+	AssumeYes bool `json:"assume_yes"`
+
+	// unexported variables
+	appState  *State
+	parent    *authCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonAuthRotateCommandOptions is just an alias for authRotateCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonAuthRotateCommandOptions authRotateCommandOptions
+
+// newAuthRotateCommandOptions returns a new object with defaults set.
+func newAuthRotateCommandOptions(state *State, parent *authCommandOptions) *authRotateCommandOptions {
+	configKey := _ConfigCommandAuthRotateKey
+	viper.SetDefault(fmt.Sprintf("%s.assume_yes", configKey), false)
+
+	return &authRotateCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *authRotateCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --yes
+	flags.BoolP("yes", "y", false, "rotate the API token without asking for confirmation")
+	viper.BindPFlag(fmt.Sprintf("%s.assume_yes", c.configKey), flags.Lookup("yes"))
+	viper.BindEnv(fmt.Sprintf("%s.assume_yes", c.configKey), fmt.Sprintf("%sASSUME_YES", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *authRotateCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *authRotateCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *authRotateCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.Auth.Rotate
+
+	// save options
+	c.AssumeYes = viperConfig.AssumeYes
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *authRotateCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'auth rotate' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *authRotateCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonAuthRotateCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *authRotateCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *authRotateCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperAuthRotateCommandOptions holds the options for the 'auth rotate' subcommand.
+type viperAuthRotateCommandOptions struct {
+	AssumeYes bool `mapstructure:"assume_yes"`
+}
@@ -12,18 +12,26 @@ import (
 // commandOptions holds options for all of the subcommands.
 type commandOptions struct {
 	// unexported variables
-	appState  *State
-	parent    *config
-	configKey string
-	isLoaded  bool
-	/*
-		configureOptions *configureCommandOptions
-		configureOptionsOnce *sync.Once
-	*/
-	provisionOptions     *provisionCommandOptions
-	provisionOptionsOnce *sync.Once
-	versionOptions       *versionCommandOptions
-	versionOptionsOnce   *sync.Once
+	appState               *State
+	parent                 *config
+	configKey              string
+	isLoaded               bool
+	accountOptions         *accountCommandOptions
+	accountOptionsOnce     *sync.Once
+	authOptions            *authCommandOptions
+	authOptionsOnce        *sync.Once
+	configureOptions       *configureCommandOptions
+	configureOptionsOnce   *sync.Once
+	deprovisionOptions     *deprovisionCommandOptions
+	deprovisionOptionsOnce *sync.Once
+	keystoreOptions        *keystoreCommandOptions
+	keystoreOptionsOnce    *sync.Once
+	provisionOptions       *provisionCommandOptions
+	provisionOptionsOnce   *sync.Once
+	userOptions            *userCommandOptions
+	userOptionsOnce        *sync.Once
+	versionOptions         *versionCommandOptions
+	versionOptionsOnce     *sync.Once
 }
 
 // jsonCommandOptions is just an alias for commandOptions that is used during marshalling and unmarshalling to
@@ -35,23 +43,80 @@ func newCommandOptions(state *State, parent *config) *commandOptions {
 	configKey := _ConfigCommandKey
 
 	return &commandOptions{
-		appState:             state,
-		parent:               parent,
-		configKey:            configKey,
-		provisionOptionsOnce: &sync.Once{},
-		versionOptionsOnce:   &sync.Once{},
+		appState:               state,
+		parent:                 parent,
+		configKey:              configKey,
+		accountOptionsOnce:     &sync.Once{},
+		authOptionsOnce:        &sync.Once{},
+		configureOptionsOnce:   &sync.Once{},
+		deprovisionOptionsOnce: &sync.Once{},
+		keystoreOptionsOnce:    &sync.Once{},
+		provisionOptionsOnce:   &sync.Once{},
+		userOptionsOnce:        &sync.Once{},
+		versionOptionsOnce:     &sync.Once{},
 	}
 }
 
+// Account returns the options for the "account" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) Account() *accountCommandOptions {
+	c.accountOptionsOnce.Do(func() {
+		c.accountOptions = newAccountCommandOptions(c.appState, c)
+	})
+	return c.accountOptions
+}
+
+// Auth returns the options for the "auth" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) Auth() *authCommandOptions {
+	c.authOptionsOnce.Do(func() {
+		c.authOptions = newAuthCommandOptions(c.appState, c)
+	})
+	return c.authOptions
+}
+
 // BindFlags is used to add command-line flags and bind them to viper configuration keys.
 func (c *commandOptions) BindFlags(cmd *cobra.Command) {
 }
 
+// Configure returns the options for the "configure" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) Configure() *configureCommandOptions {
+	c.configureOptionsOnce.Do(func() {
+		c.configureOptions = newConfigureCommandOptions(c.appState, c)
+	})
+	return c.configureOptions
+}
+
 // ConfigKey returns the base name of the viper configuration key where the options are stored.
 func (c *commandOptions) ConfigKey() string {
 	return c.configKey
 }
 
+// Deprovision returns the options for the "deprovision" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) Deprovision() *deprovisionCommandOptions {
+	c.deprovisionOptionsOnce.Do(func() {
+		c.deprovisionOptions = newDeprovisionCommandOptions(c.appState, c, _ConfigCommandDeprovisionKey,
+			func() viperDeprovisionCommandOptions {
+				return c.appState.config.viperConfig.CommandOptions.Deprovision
+			})
+	})
+	return c.deprovisionOptions
+}
+
 // IsLoaded returns whether or not the configuration settings have been loaded.
 func (c *commandOptions) IsLoaded() bool {
 	return c.isLoaded
@@ -73,6 +138,18 @@ func (c *commandOptions) Load() errorx.Error {
 	return nil
 }
 
+// Keystore returns the options for the "keystore" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) Keystore() *keystoreCommandOptions {
+	c.keystoreOptionsOnce.Do(func() {
+		c.keystoreOptions = newKeystoreCommandOptions(c.appState, c)
+	})
+	return c.keystoreOptions
+}
+
 // LogSettings simply writes the object settings to the log.
 //
 // If recurse is true, the global options are logged as well.
@@ -126,6 +203,18 @@ func (c *commandOptions) String() string {
 	return string(output)
 }
 
+// User returns the options for the "user" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *commandOptions) User() *userCommandOptions {
+	c.userOptionsOnce.Do(func() {
+		c.userOptions = newUserCommandOptions(c.appState, c)
+	})
+	return c.userOptions
+}
+
 // VersionOptions returns the options for the "version" subcommand.
 //
 // If the options object has not been initialized, it is automatically initialized. However, the settings
@@ -140,6 +229,11 @@ func (c *commandOptions) Version() *versionCommandOptions {
 
 // viperCommandOptions holds the options for all subcommands.
 type viperCommandOptions struct {
-	Provision viperProvisionCommandOptions `mapstructure:"provision"`
-	Version   viperVersionCommandOptions   `mapstructure:"version"`
+	Account     viperAccountCommandOptions     `mapstructure:"account"`
+	Auth        viperAuthCommandOptions        `mapstructure:"auth"`
+	Configure   viperConfigureCommandOptions   `mapstructure:"configure"`
+	Deprovision viperDeprovisionCommandOptions `mapstructure:"deprovision"`
+	Provision   viperProvisionCommandOptions   `mapstructure:"provision"`
+	User        viperUserCommandOptions        `mapstructure:"user"`
+	Version     viperVersionCommandOptions     `mapstructure:"version"`
 }
@@ -1,13 +1,18 @@
 package app
 
 import (
+	"bytes"
 	goerrors "errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 	"go.joshhogle.dev/errorx"
 	"go.joshhogle.dev/s1cli/internal/errors"
+	"gopkg.in/yaml.v3"
 )
 
 // config is an internal structure to hold the application configuration.
@@ -49,6 +54,157 @@ func (c *config) IsLoaded() bool {
 	return c.isLoaded
 }
 
+// applyProfile merges the settings of the selected profile into the settings read from the configuration file.
+//
+// A profile holds any of the global settings along with an optional 'command' section holding command settings. It
+// may also name another profile to inherit settings from using the 'inherits' setting. Settings in the profile
+// replace those in the configuration file, while flags and environment variables still replace profile settings.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *config) applyProfile() errorx.Error {
+	name := viper.GetString(fmt.Sprintf("%s.profile", _ConfigGlobalKey))
+	if name == "" {
+		return nil
+	}
+	logger := c.appState.Logger().With().Str("profile", name).Logger()
+
+	settings, errx := c.resolveProfile(name, []string{})
+	if errx != nil {
+		return errx
+	}
+	merged := map[string]any{}
+	if commandSettings, ok := settings[_ConfigCommandKey].(map[string]any); ok {
+		merged[_ConfigCommandKey] = commandSettings
+	}
+	delete(settings, _ConfigCommandKey)
+	delete(settings, _ProfileInheritsKey)
+	delete(settings, "profile")
+	merged[_ConfigGlobalKey] = settings
+	if err := viper.MergeConfigMap(merged); err != nil {
+		errx := errors.NewConfigParseFailure(viper.ConfigFileUsed(), err)
+		logger.Error().Err(errx).Str("config_file", viper.ConfigFileUsed()).Msg(errx.Error())
+		return errx
+	}
+	logger.Debug().Msg("applied profile settings")
+	return nil
+}
+
+// WriteFile writes the given settings to a YAML configuration file.
+//
+// The settings map viper configuration keys (eg: global.api_key) to their values. Since the file holds the API key,
+// it is created so that only the current user is able to read it. If overwrite is false and the file already exists,
+// the file is left untouched and an error is returned. Otherwise the settings are merged into the existing file so
+// that any other settings it holds are kept.
+//
+// The following errors are returned by this function:
+// ConfigWriteFailure
+func (c *config) WriteFile(file string, settings map[string]any, overwrite bool) errorx.Error {
+	logger := c.appState.Logger().With().Str("config_file", file).Logger()
+
+	// an existing file is updated in place so that any other settings, profiles and comments are kept
+	var err error
+	if _, statErr := os.Stat(file); statErr == nil && overwrite {
+		var doc *yaml.Node
+		if doc, err = readYAMLFile(file); err == nil {
+			keys := make([]string, 0, len(settings))
+			for k := range settings {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if err = setYAMLSetting(doc, strings.Split(k, "."), settings[k]); err != nil {
+					break
+				}
+			}
+		}
+		if err == nil {
+			err = writeYAMLFile(file, doc)
+		}
+	} else {
+		v := viper.New()
+		v.SetConfigType("yaml")
+		v.SetConfigPermissions(0600)
+		for k, val := range settings {
+			v.Set(k, val)
+		}
+
+		// write the file
+		if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+			errx := errors.NewConfigWriteFailure(file, err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		if overwrite {
+			err = v.WriteConfigAs(file)
+		} else {
+			err = v.SafeWriteConfigAs(file)
+		}
+	}
+	if err != nil {
+		if _, ok := err.(viper.ConfigFileAlreadyExistsError); ok {
+			err = goerrors.New("file already exists")
+		}
+		errx := errors.NewConfigWriteFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// an existing file keeps its original permissions when overwritten so make sure they are restricted
+	if err := os.Chmod(file, 0600); err != nil {
+		errx := errors.NewConfigWriteFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	return nil
+}
+
+// fileSetting returns the value of a single setting in the configuration file that was loaded.
+//
+// Unlike viper, the value is read straight from the file so it is not affected by flags, environment variables or
+// profiles. The path holds the keys leading to the setting (eg: global, api_key).
+//
+// The following errors are returned by this function:
+// ConfigLoadFailure
+func (c *config) fileSetting(path []string) (string, errorx.Error) {
+	file := c.globalOptions.ConfigFile
+	logger := c.appState.Logger().With().Str("config_file", file).Str("setting", strings.Join(path, ".")).Logger()
+
+	_, node, err := readYAMLSetting(file, path)
+	if err != nil {
+		errx := errors.NewConfigLoadFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return "", errx
+	}
+	return node.Value, nil
+}
+
+// updateFileSetting replaces the value of a single setting in the configuration file that was loaded.
+//
+// The file is edited in place so that comments and the order of settings are kept. The setting must already exist
+// in the file. The file is replaced atomically and keeps its original permissions.
+//
+// The following errors are returned by this function:
+// ConfigWriteFailure
+func (c *config) updateFileSetting(path []string, value string) errorx.Error {
+	file := c.globalOptions.ConfigFile
+	logger := c.appState.Logger().With().Str("config_file", file).Str("setting", strings.Join(path, ".")).Logger()
+
+	doc, node, err := readYAMLSetting(file, path)
+	if err == nil {
+		node.Value = value
+		node.Tag = "!!str"
+		err = writeYAMLFile(file, doc)
+	}
+	if err != nil {
+		errx := errors.NewConfigWriteFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	logger.Debug().Msg("updated setting in configuration file")
+	return nil
+}
+
 // load simply loads the configuration settings into memory.
 //
 // It is the caller's responsibility to validate the configuration settings once they have been loaded.
@@ -150,6 +306,48 @@ func (c *config) loadFile(file string) errorx.Error {
 	return c.unmarshal()
 }
 
+// resolveProfile returns the settings for the given profile, including any settings it inherits.
+//
+// The chain holds the names of the profiles which inherit from this one and is used to detect loops.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *config) resolveProfile(name string, chain []string) (map[string]any, errorx.Error) {
+	logger := c.appState.Logger()
+	configFile := viper.ConfigFileUsed()
+
+	// profile names are not case sensitive since viper lowercases all keys
+	name = strings.ToLower(name)
+	chain = append(chain, name)
+	for _, n := range chain[:len(chain)-1] {
+		if n == name {
+			errx := errors.NewConfigValidateFailure(configFile, _ConfigProfilesKey, name,
+				fmt.Errorf("profiles inherit from each other in a loop: %s", strings.Join(chain, " -> ")))
+			logger.Error().Err(errx).Str("option", _ConfigProfilesKey).Str("value", name).Msg(errx.Error())
+			return nil, errx
+		}
+	}
+	profile := viper.GetStringMap(fmt.Sprintf("%s.%s", _ConfigProfilesKey, name))
+	if len(profile) == 0 {
+		errx := errors.NewConfigValidateFailure(configFile, _ConfigProfilesKey, name,
+			fmt.Errorf("profile '%s' does not exist", name))
+		logger.Error().Err(errx).Str("option", _ConfigProfilesKey).Str("value", name).Msg(errx.Error())
+		return nil, errx
+	}
+
+	// start with the inherited settings and then apply this profile's settings over the top
+	settings := map[string]any{}
+	if parent, ok := profile[_ProfileInheritsKey].(string); ok && parent != "" {
+		parentSettings, errx := c.resolveProfile(parent, chain)
+		if errx != nil {
+			return nil, errx
+		}
+		settings = parentSettings
+	}
+	mergeSettings(settings, profile)
+	return settings, nil
+}
+
 // unmarshal simply unmarshals the data from the config file into the object.
 //
 // The following errors are returned by this function:
@@ -157,6 +355,10 @@ func (c *config) loadFile(file string) errorx.Error {
 func (c *config) unmarshal() errorx.Error {
 	logger := c.appState.Logger()
 
+	if errx := c.applyProfile(); errx != nil {
+		return errx
+	}
+
 	var viperCfg viperConfig
 	if err := viper.Unmarshal(&viperCfg); err != nil {
 		errx := errors.NewConfigParseFailure(viper.ConfigFileUsed(), err)
@@ -170,6 +372,151 @@ func (c *config) unmarshal() errorx.Error {
 	return nil
 }
 
+// mergeSettings copies the settings in src into dst, merging nested sections rather than replacing them.
+func mergeSettings(dst, src map[string]any) {
+	for k, v := range src {
+		srcSection, srcOk := v.(map[string]any)
+		dstSection, dstOk := dst[k].(map[string]any)
+		if srcOk && dstOk {
+			mergeSettings(dstSection, srcSection)
+			continue
+		}
+		if srcOk {
+			section := map[string]any{}
+			mergeSettings(section, srcSection)
+			v = section
+		}
+		dst[k] = v
+	}
+}
+
+// readYAMLSetting parses the given YAML file and returns the document along with the node holding the value of the
+// setting at the given path.
+//
+// Keys are matched without regard to case since viper treats them that way.
+func readYAMLSetting(file string, path []string) (*yaml.Node, *yaml.Node, error) {
+	if file == "" {
+		return nil, nil, goerrors.New("no configuration file was loaded")
+	}
+	doc, err := readYAMLFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, fmt.Errorf("setting '%s' is not in the file", strings.Join(path, "."))
+	}
+	node := doc.Content[0]
+	for _, key := range path {
+		var value *yaml.Node
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if strings.EqualFold(node.Content[i].Value, key) {
+					value = node.Content[i+1]
+				}
+			}
+		}
+		if value == nil {
+			return nil, nil, fmt.Errorf("setting '%s' is not in the file", strings.Join(path, "."))
+		}
+		node = value
+	}
+	if node.Kind != yaml.ScalarNode {
+		return nil, nil, fmt.Errorf("setting '%s' is not a single value", strings.Join(path, "."))
+	}
+	return doc, node, nil
+}
+
+// readYAMLFile parses the given YAML file and returns the document.
+func readYAMLFile(file string) (*yaml.Node, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// setYAMLSetting sets the value of the setting at the given path in the YAML document.
+//
+// Any sections leading to the setting which are missing are added to the end of their parent section. Keys are matched
+// without regard to case since viper treats them that way.
+func setYAMLSetting(doc *yaml.Node, path []string, value any) error {
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	node := doc.Content[0]
+	for i, key := range path {
+		// an empty section is parsed as a null value
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+			node.Kind, node.Tag, node.Value = yaml.MappingNode, "!!map", ""
+		}
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("setting '%s' is not a section", strings.Join(path[:i], "."))
+		}
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if strings.EqualFold(node.Content[j].Value, key) {
+				child = node.Content[j+1]
+			}
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		node = child
+	}
+
+	// keep any comments attached to the existing value
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	valueNode.HeadComment, valueNode.LineComment, valueNode.FootComment =
+		node.HeadComment, node.LineComment, node.FootComment
+	*node = valueNode
+	return nil
+}
+
+// writeYAMLFile replaces the given file with the YAML document.
+func writeYAMLFile(file string, doc *yaml.Node) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so the original is not lost if anything fails
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
 // viperConfig is used for unmarshaling the configuration file, environment variables and CLI flags using viper.
 type viperConfig struct {
 	GlobalOptions  viperGlobalOptions  `mapstructure:"global"`
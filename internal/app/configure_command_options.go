@@ -0,0 +1,155 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// configureCommandOptions holds specific settings for the 'configure' subcommand.
+type configureCommandOptions struct {
+	// Force represents a flag used to determine whether to update an existing configuration file or not.
+	Force bool `json:"force"`
+
+	// OutputFile is the configuration file to write.
+	OutputFile string `json:"output_file"`
+
+	// unexported variables
+	appState  *State
+	parent    *commandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonConfigureCommandOptions is just an alias for configureCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonConfigureCommandOptions configureCommandOptions
+
+// newConfigureCommandOptions returns a new object with defaults set.
+func newConfigureCommandOptions(state *State, parent *commandOptions) *configureCommandOptions {
+	configKey := _ConfigCommandConfigureKey
+	viper.SetDefault(fmt.Sprintf("%s.force", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.output_file", configKey),
+		filepath.Join(_DefaultConfigDir, fmt.Sprintf("%s.yaml", _DefaultConfigFileBaseName)))
+
+	return &configureCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *configureCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	flags.Bool("force", false, "update the configuration file if it already exists")
+	viper.BindPFlag(fmt.Sprintf("%s.force", c.configKey), flags.Lookup("force"))
+	viper.BindEnv(fmt.Sprintf("%s.force", c.configKey), fmt.Sprintf("%sFORCE", envPrefix))
+
+	flags.String("output-file", filepath.Join(_DefaultConfigDir, fmt.Sprintf("%s.yaml", _DefaultConfigFileBaseName)),
+		"configuration file to write")
+	viper.BindPFlag(fmt.Sprintf("%s.output_file", c.configKey), flags.Lookup("output-file"))
+	viper.BindEnv(fmt.Sprintf("%s.output_file", c.configKey), fmt.Sprintf("%sOUTPUT_FILE", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *configureCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *configureCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *configureCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	logger := c.appState.logger
+	viperConfig := c.appState.config.viperConfig.CommandOptions.Configure
+
+	// output file must be a valid path
+	outputFile, err := filepath.Abs(os.ExpandEnv(viperConfig.OutputFile))
+	if err != nil {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "output_file",
+			viperConfig.OutputFile, err)
+		logger.Error().
+			Err(errx).
+			Str("option", "output_file").
+			Str("value", viperConfig.OutputFile).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// save options
+	c.Force = viperConfig.Force
+	c.OutputFile = outputFile
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *configureCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.Logger().Debug().Any("options", c.StringMap()).Msg("loaded 'configure' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *configureCommandOptions) MarshalJSON() ([]byte, error) {
+	opt := jsonConfigureCommandOptions(*c)
+	return json.Marshal(&opt)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *configureCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *configureCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperConfigureCommandOptions holds the options for the 'configure' subcommand.
+type viperConfigureCommandOptions struct {
+	Force      bool   `mapstructure:"force"`
+	OutputFile string `mapstructure:"output_file"`
+}
@@ -1,22 +1,101 @@
 package app
 
+import (
+	"time"
+
+	"go.joshhogle.dev/s1cli/internal/build"
+)
+
 // Configuration keys.
 const (
 	_ConfigGlobalKey                  = "global"
 	_ConfigCommandKey                 = "command"
+	_ConfigCommandAccountKey          = "command.account"
+	_ConfigCommandAccountExpireKey    = "command.account.expire"
+	_ConfigCommandAccountExtendKey    = "command.account.extend"
+	_ConfigCommandAccountListKey      = "command.account.list"
+	_ConfigCommandAuthKey             = "command.auth"
+	_ConfigCommandAuthRotateKey       = "command.auth.rotate"
+	_ConfigCommandConfigureKey        = "command.configure"
+	_ConfigCommandDeprovisionKey      = "command.deprovision"
+	_ConfigCommandKeystoreKey         = "command.keystore"
+	_ConfigCommandUserKey             = "command.user"
+	_ConfigCommandUserAddRoleKey      = "command.user.add_role"
+	_ConfigCommandUserCreateKey       = "command.user.create"
+	_ConfigCommandUserDeleteKey       = "command.user.delete"
+	_ConfigCommandUserListKey         = "command.user.list"
+	_ConfigCommandUserRemoveRoleKey   = "command.user.remove_role"
 	_ConfigCommandVersionKey          = "command.version"
 	_ConfigCommandProvisionKey        = "command.provision"
 	_ConfigCommandProvisionAccountKey = "command.provision.account"
+	_ConfigProfilesKey                = "profiles"
 )
 
 // Default configuration settings.
 const (
-	_DefaultConfigDir          = "."
-	_DefaultConfigFileBaseName = "config"
-	_DefaultCSVSeparator       = ","
+	_DefaultAccountType          = "Trial"
+	_DefaultBillingMode          = "subscription"
+	_DefaultConfigDir            = "."
+	_DefaultConfigFileBaseName   = "config"
+	_DefaultCSVSeparator         = ","
+	_DefaultKeystoreFileName     = "keystore.json"
+	_DefaultMaxRetries           = 3
+	_DefaultPasswordClasses      = "lower,upper,digit,symbol"
+	_DefaultPasswordLength       = 32
+	_DefaultProvisionConcurrency = 1
+	_DefaultRateLimit            = 10.0
+	_DefaultRequestTimeout       = 60 * time.Second
+	_DefaultRetryMaxWait         = 30 * time.Second
+	_DefaultRetryWait            = 1 * time.Second
+	_DefaultTokenExpiryWarning   = 14 * 24 * time.Hour
+	_DefaultUsageType            = "customer"
+	_DefaultUserRole             = "Admin"
+)
+
+// Results file formats.
+const (
+	_ResultsFormatCSV  = "csv"
+	_ResultsFormatJSON = "json"
+)
+
+// Profile settings.
+const (
+	_ProfileInheritsKey = "inherits"
+)
+
+// Environment variables.
+const (
+	_EnvKeystorePassphrase = build.AppEnvPrefix + "KEYSTORE_PASSPHRASE"
 )
 
 // Global flag names.
 const (
 	_FlagGlobalOptionsLogLevel = "log-level"
 )
+
+// _RedactedValue replaces secret values when settings are logged or displayed.
+const _RedactedValue = "<redacted>"
+
+// Limits on configuration settings.
+const (
+	// MaxProvisionConcurrency is the largest number of accounts that can be provisioned at the same time.
+	MaxProvisionConcurrency = 50
+
+	_MinPasswordLength = 8
+)
+
+// _DefaultLicenseSettings holds the license settings given to new accounts unless other settings are configured.
+var _DefaultLicenseSettings = map[string]string{
+	"account_level_ranger":      "Account",
+	"dv_retention":              "30 Days",
+	"malicious_data_retention":  "365 Days",
+	"marketplace_access_status": "Available",
+	"remote_shell_availability": "Enabled",
+}
+
+// _DefaultRateLimitOverrides holds the default number of requests per second allowed for endpoints which are more
+// expensive to call than others.
+var _DefaultRateLimitOverrides = map[string]float64{
+	"/accounts": 2,
+	"/users":    5,
+}
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// parentCommandOptions is implemented by any options object which may be the parent of deprovisionCommandOptions.
+type parentCommandOptions interface {
+	Load() errorx.Error
+	LogSettings(recurse bool)
+}
+
+// deprovisionCommandOptions holds options for the 'deprovision' and 'account expire' subcommands.
+//
+// Both subcommands do the same thing, so they share this object but store their settings under different keys.
+type deprovisionCommandOptions struct {
+	AccountIDs   string `json:"account_ids"`
+	AccountNames string `json:"account_names"`
+	AssumeYes    bool   `json:"assume_yes"`
+	CSVSeparator string `json:"csv_separator"`
+	CSVSource    string `json:"csv_source"`
+	DeleteUsers  bool   `json:"delete_users"`
+	DryRun       bool   `json:"dry_run"`
+	KeepUsers    bool   `json:"keep_users"`
+
+	// unexported variables
+	appState    *State
+	parent      parentCommandOptions
+	configKey   string
+	isLoaded    bool
+	viperConfig func() viperDeprovisionCommandOptions
+}
+
+// jsonDeprovisionCommandOptions is just an alias for deprovisionCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonDeprovisionCommandOptions deprovisionCommandOptions
+
+// newDeprovisionCommandOptions returns a new object with defaults set.
+//
+// The settings are stored under the given configuration key and are read from the viper configuration returned by
+// viperConfig.
+func newDeprovisionCommandOptions(state *State, parent parentCommandOptions, configKey string,
+	viperConfig func() viperDeprovisionCommandOptions) *deprovisionCommandOptions {
+
+	viper.SetDefault(fmt.Sprintf("%s.account_ids", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_names", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.assume_yes", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.csv_separator", configKey), _DefaultCSVSeparator)
+	viper.SetDefault(fmt.Sprintf("%s.csv_source", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.delete_users", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.dry_run", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.keep_users", configKey), false)
+
+	return &deprovisionCommandOptions{
+		CSVSeparator: _DefaultCSVSeparator,
+		appState:     state,
+		parent:       parent,
+		configKey:    configKey,
+		viperConfig:  viperConfig,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *deprovisionCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-id
+	flags.String("account-id", "", "comma-separated list of IDs of the accounts to expire")
+	viper.BindPFlag(fmt.Sprintf("%s.account_ids", c.configKey), flags.Lookup("account-id"))
+	viper.BindEnv(fmt.Sprintf("%s.account_ids", c.configKey), fmt.Sprintf("%sACCOUNT_IDS", envPrefix))
+
+	// --account-name
+	flags.String("account-name", "", "comma-separated list of names of the accounts to expire")
+	viper.BindPFlag(fmt.Sprintf("%s.account_names", c.configKey), flags.Lookup("account-name"))
+	viper.BindEnv(fmt.Sprintf("%s.account_names", c.configKey), fmt.Sprintf("%sACCOUNT_NAMES", envPrefix))
+
+	// --csv-separator
+	flags.String("csv-separator", _DefaultCSVSeparator, "when using a CSV, this is the separator token")
+	viper.BindPFlag(fmt.Sprintf("%s.csv_separator", c.configKey), flags.Lookup("csv-separator"))
+	viper.BindEnv(fmt.Sprintf("%s.csv_separator", c.configKey), fmt.Sprintf("%sCSV_SEPARATOR", envPrefix))
+
+	// --csv-source
+	flags.String("csv-source", "", "expire the accounts in the given CSV file used to provision them")
+	viper.BindPFlag(fmt.Sprintf("%s.csv_source", c.configKey), flags.Lookup("csv-source"))
+	viper.BindEnv(fmt.Sprintf("%s.csv_source", c.configKey), fmt.Sprintf("%sCSV_SOURCE", envPrefix))
+
+	// --delete-users
+	flags.Bool("delete-users", false, "delete users who only belong to the expired accounts")
+	viper.BindPFlag(fmt.Sprintf("%s.delete_users", c.configKey), flags.Lookup("delete-users"))
+	viper.BindEnv(fmt.Sprintf("%s.delete_users", c.configKey), fmt.Sprintf("%sDELETE_USERS", envPrefix))
+
+	// --dry-run
+	flags.Bool("dry-run", false, "show what would be changed without making any changes")
+	viper.BindPFlag(fmt.Sprintf("%s.dry_run", c.configKey), flags.Lookup("dry-run"))
+	viper.BindEnv(fmt.Sprintf("%s.dry_run", c.configKey), fmt.Sprintf("%sDRY_RUN", envPrefix))
+
+	// --keep-users
+	flags.Bool("keep-users", false, "leave the users of the accounts alone")
+	viper.BindPFlag(fmt.Sprintf("%s.keep_users", c.configKey), flags.Lookup("keep-users"))
+	viper.BindEnv(fmt.Sprintf("%s.keep_users", c.configKey), fmt.Sprintf("%sKEEP_USERS", envPrefix))
+
+	// --yes
+	flags.BoolP("yes", "y", false, "expire the accounts without asking for confirmation")
+	viper.BindPFlag(fmt.Sprintf("%s.assume_yes", c.configKey), flags.Lookup("yes"))
+	viper.BindEnv(fmt.Sprintf("%s.assume_yes", c.configKey), fmt.Sprintf("%sASSUME_YES", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *deprovisionCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *deprovisionCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *deprovisionCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.viperConfig()
+	logger := c.appState.logger
+
+	// at least one account must be given
+	if viperConfig.AccountIDs == "" && viperConfig.AccountNames == "" && viperConfig.CSVSource == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_source",
+			viperConfig.CSVSource, goerrors.New("an account ID, account name or CSV file is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "csv_source").
+			Str("value", viperConfig.CSVSource).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// users cannot be both deleted and left alone
+	if viperConfig.DeleteUsers && viperConfig.KeepUsers {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "delete_users",
+			viperConfig.DeleteUsers, goerrors.New("delete_users and keep_users cannot both be set"))
+		logger.Error().
+			Err(errx).
+			Str("option", "delete_users").
+			Bool("value", viperConfig.DeleteUsers).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// using a CSV file
+	if viperConfig.CSVSource != "" {
+		// CSV separator cannot be empty
+		if viperConfig.CSVSeparator == "" {
+			viperConfig.CSVSeparator = _DefaultCSVSeparator
+			logger.Warn().Msgf("an empty CSV separator is not allowed ; defaulting to %s for separator",
+				_DefaultCSVSeparator)
+		}
+
+		// special TAB case
+		if strings.EqualFold(viperConfig.CSVSeparator, "tab") {
+			viperConfig.CSVSeparator = "\t"
+		}
+
+		// CSV separator should be a single character
+		if len(viperConfig.CSVSeparator) != 1 {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_separator",
+				viperConfig.CSVSeparator, goerrors.New("CSV separator must be a single character"))
+			logger.Error().
+				Err(errx).
+				Str("option", "csv_separator").
+				Str("value", viperConfig.CSVSeparator).
+				Msg(errx.Error())
+			return errx
+		}
+
+		// make sure CSV file exists
+		_, err := os.Stat(viperConfig.CSVSource)
+		if err != nil {
+			errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "csv_source",
+				viperConfig.CSVSource, err)
+			logger.Error().
+				Err(errx).
+				Str("option", "csv_source").
+				Str("value", viperConfig.CSVSource).
+				Msg(errx.Error())
+			return errx
+		}
+	}
+
+	// save options
+	c.AccountIDs = viperConfig.AccountIDs
+	c.AccountNames = viperConfig.AccountNames
+	c.AssumeYes = viperConfig.AssumeYes
+	c.CSVSeparator = viperConfig.CSVSeparator
+	c.CSVSource = viperConfig.CSVSource
+	c.DeleteUsers = viperConfig.DeleteUsers
+	c.DryRun = viperConfig.DryRun
+	c.KeepUsers = viperConfig.KeepUsers
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *deprovisionCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	name := strings.ReplaceAll(strings.TrimPrefix(c.configKey, _ConfigCommandKey+"."), ".", " ")
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msgf("loaded '%s' subcommand options", name)
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *deprovisionCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonDeprovisionCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *deprovisionCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *deprovisionCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperDeprovisionCommandOptions holds the options for the 'deprovision' and 'account expire' subcommands.
+type viperDeprovisionCommandOptions struct {
+	AccountIDs   string `mapstructure:"account_ids"`
+	AccountNames string `mapstructure:"account_names"`
+	AssumeYes    bool   `mapstructure:"assume_yes"`
+	CSVSeparator string `mapstructure:"csv_separator"`
+	CSVSource    string `mapstructure:"csv_source"`
+	DeleteUsers  bool   `mapstructure:"delete_users"`
+	DryRun       bool   `mapstructure:"dry_run"`
+	KeepUsers    bool   `mapstructure:"keep_users"`
+}
@@ -2,9 +2,14 @@ package app
 
 import (
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
@@ -12,12 +17,17 @@ import (
 	"go.joshhogle.dev/errorx"
 	"go.joshhogle.dev/s1cli/internal/build"
 	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/keystore"
+	"go.joshhogle.dev/s1cli/internal/secrets"
 )
 
 // globalOptions holds global configuration settings.
 type globalOptions struct {
 	// APIKey is the API key to use for authentication with the SentinelOne API.
-	APIKey string `json:"api_key"`
+	//
+	// The value may also be a reference to where the key is stored (eg: keystore://prod). Use ResolveAPIKey() to get
+	// the actual key.
+	APIKey string `json:"api_key" redact:"true"`
 
 	// ConfigDir is the directory in which the configuration file is located.
 	ConfigDir string `json:"config_dir"`
@@ -25,17 +35,55 @@ type globalOptions struct {
 	// ConfigFile is the configuration file from which the configuration was read.
 	ConfigFile string `json:"config_file"`
 
+	// KeystoreFile is the path to the encrypted keystore used to hold secrets such as API keys.
+	KeystoreFile string `json:"keystore_file"`
+
 	// LogLevel identifies the minimum level of messages to log.
 	LogLevel zerolog.Level `json:"log_level"`
 
+	// MaxRetries is the maximum number of times a failed API request is retried.
+	MaxRetries int `json:"max_retries"`
+
+	// Output is the format in which commands write their results.
+	Output string `json:"output"`
+
+	// Profile is the name of the profile in the configuration file whose settings are applied.
+	Profile string `json:"profile"`
+
+	// RateLimit is the maximum number of API requests to send per second. A value of 0 disables rate limiting.
+	RateLimit float64 `json:"rate_limit"`
+
+	// RateLimitOverrides maps API endpoints to their own maximum number of requests per second. Requests to these
+	// endpoints are also limited by RateLimit.
+	RateLimitOverrides map[string]float64 `json:"rate_limit_overrides"`
+
+	// RequestTimeout is the maximum amount of time to wait for a single API request to complete.
+	RequestTimeout time.Duration `json:"request_timeout"`
+
+	// RetryMaxWait is the maximum amount of time to wait between retries of a failed API request.
+	RetryMaxWait time.Duration `json:"retry_max_wait"`
+
+	// RetryWait is the initial amount of time to wait before retrying a failed API request.
+	RetryWait time.Duration `json:"retry_wait"`
+
+	// Template is the Go text/template used to write results when the output format is 'template'.
+	Template string `json:"template"`
+
 	// TenantURL is the URL for the customer's SentinelOne SaaS tenant.
 	TenantURL string `json:"tenant_url"`
 
+	// TokenExpiryWarning is how long before the API token expires to start warning that it needs to be rotated.
+	TokenExpiryWarning time.Duration `json:"token_expiry_warning"`
+
 	// unexported variables
-	appState  *State
-	parent    *config
-	configKey string
-	isLoaded  bool
+	appState       *State
+	parent         *config
+	configKey      string
+	isLoaded       bool
+	keystore       *keystore.Keystore
+	outputTemplate *template.Template
+	resolvedAPIKey string
+	secrets        *secrets.Resolver
 }
 
 // jsonGlobalOptions is just an alias for globalOptions that is used during marshalling and unmarshalling to
@@ -46,12 +94,23 @@ type jsonGlobalOptions globalOptions
 func newGlobalOptions(state *State, parent *config) *globalOptions {
 	configKey := _ConfigGlobalKey
 	viper.SetDefault(fmt.Sprintf("%s.api_key", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.keystore_file", configKey), "")
 	if state.productInfo.IsDeveloperBuild {
 		viper.SetDefault(fmt.Sprintf("%s.log_level", configKey), zerolog.DebugLevel)
 	} else {
 		viper.SetDefault(fmt.Sprintf("%s.log_level", configKey), zerolog.InfoLevel)
 	}
+	viper.SetDefault(fmt.Sprintf("%s.max_retries", configKey), _DefaultMaxRetries)
+	viper.SetDefault(fmt.Sprintf("%s.output", configKey), OutputFormatTable)
+	viper.SetDefault(fmt.Sprintf("%s.profile", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.rate_limit", configKey), _DefaultRateLimit)
+	viper.SetDefault(fmt.Sprintf("%s.rate_limit_overrides", configKey), _DefaultRateLimitOverrides)
+	viper.SetDefault(fmt.Sprintf("%s.request_timeout", configKey), _DefaultRequestTimeout)
+	viper.SetDefault(fmt.Sprintf("%s.retry_max_wait", configKey), _DefaultRetryMaxWait)
+	viper.SetDefault(fmt.Sprintf("%s.retry_wait", configKey), _DefaultRetryWait)
+	viper.SetDefault(fmt.Sprintf("%s.template", configKey), "")
 	viper.SetDefault(fmt.Sprintf("%s.tenant_url", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.token_expiry_warning", configKey), _DefaultTokenExpiryWarning)
 
 	return &globalOptions{
 		appState:  state,
@@ -66,7 +125,8 @@ func (c *globalOptions) BindFlags(cmd *cobra.Command) {
 	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
 
 	// API key
-	persistentFlags.StringP("api-key", "k", "", "SentinelOne API key")
+	persistentFlags.StringP("api-key", "k", "",
+		"SentinelOne API key or a reference to it using file://, env://, cmd:// or keystore://")
 	viper.BindPFlag(fmt.Sprintf("%s.api_key", c.configKey), persistentFlags.Lookup("api-key"))
 	viper.BindEnv(fmt.Sprintf("%s.api_key", c.configKey), fmt.Sprintf("%sAPI_KEY", envPrefix))
 
@@ -75,6 +135,11 @@ func (c *globalOptions) BindFlags(cmd *cobra.Command) {
 	viper.BindPFlag(fmt.Sprintf("%s.config_file", c.configKey), persistentFlags.Lookup("config-file"))
 	viper.BindEnv(fmt.Sprintf("%s.config_file", c.configKey), fmt.Sprintf("%sCONFIG_FILE", envPrefix))
 
+	// keystore file
+	persistentFlags.String("keystore-file", "", "path to the encrypted keystore holding secrets")
+	viper.BindPFlag(fmt.Sprintf("%s.keystore_file", c.configKey), persistentFlags.Lookup("keystore-file"))
+	viper.BindEnv(fmt.Sprintf("%s.keystore_file", c.configKey), fmt.Sprintf("%sKEYSTORE_FILE", envPrefix))
+
 	// log level
 	usage := "set logging level to trace, debug, info, notice, warn, error, fatal or panic"
 	if c.appState.productInfo.IsDeveloperBuild {
@@ -85,10 +150,65 @@ func (c *globalOptions) BindFlags(cmd *cobra.Command) {
 	viper.BindPFlag(fmt.Sprintf("%s.log_level", c.configKey), persistentFlags.Lookup(_FlagGlobalOptionsLogLevel))
 	viper.BindEnv(fmt.Sprintf("%s.log_level", c.configKey), fmt.Sprintf("%s_LOG_LEVEL", envPrefix))
 
+	// max retries
+	persistentFlags.Int("max-retries", _DefaultMaxRetries, "maximum number of times to retry a failed API request")
+	viper.BindPFlag(fmt.Sprintf("%s.max_retries", c.configKey), persistentFlags.Lookup("max-retries"))
+	viper.BindEnv(fmt.Sprintf("%s.max_retries", c.configKey), fmt.Sprintf("%sMAX_RETRIES", envPrefix))
+
+	// output
+	persistentFlags.StringP("output", "o", OutputFormatTable,
+		fmt.Sprintf("format of the command output: %s", strings.Join(OutputFormats, ", ")))
+	viper.BindPFlag(fmt.Sprintf("%s.output", c.configKey), persistentFlags.Lookup("output"))
+	viper.BindEnv(fmt.Sprintf("%s.output", c.configKey), fmt.Sprintf("%sOUTPUT", envPrefix))
+
+	// profile
+	persistentFlags.StringP("profile", "p", "", "name of the profile in the configuration file to use")
+	viper.BindPFlag(fmt.Sprintf("%s.profile", c.configKey), persistentFlags.Lookup("profile"))
+	viper.BindEnv(fmt.Sprintf("%s.profile", c.configKey), fmt.Sprintf("%sPROFILE", build.AppEnvPrefix),
+		fmt.Sprintf("%sPROFILE", envPrefix))
+
+	// rate limit
+	persistentFlags.Float64("rate-limit", _DefaultRateLimit,
+		"maximum number of API requests to send per second (0 disables rate limiting)")
+	viper.BindPFlag(fmt.Sprintf("%s.rate_limit", c.configKey), persistentFlags.Lookup("rate-limit"))
+	viper.BindEnv(fmt.Sprintf("%s.rate_limit", c.configKey), fmt.Sprintf("%sRATE_LIMIT", envPrefix))
+
+	// request timeout
+	persistentFlags.Duration("request-timeout", _DefaultRequestTimeout,
+		"maximum amount of time to wait for an API request to complete")
+	viper.BindPFlag(fmt.Sprintf("%s.request_timeout", c.configKey), persistentFlags.Lookup("request-timeout"))
+	viper.BindEnv(fmt.Sprintf("%s.request_timeout", c.configKey), fmt.Sprintf("%sREQUEST_TIMEOUT", envPrefix))
+
+	// retry max wait
+	persistentFlags.Duration("retry-max-wait", _DefaultRetryMaxWait,
+		"maximum amount of time to wait between retries of a failed API request")
+	viper.BindPFlag(fmt.Sprintf("%s.retry_max_wait", c.configKey), persistentFlags.Lookup("retry-max-wait"))
+	viper.BindEnv(fmt.Sprintf("%s.retry_max_wait", c.configKey), fmt.Sprintf("%sRETRY_MAX_WAIT", envPrefix))
+
+	// retry wait
+	persistentFlags.Duration("retry-wait", _DefaultRetryWait,
+		"initial amount of time to wait before retrying a failed API request")
+	viper.BindPFlag(fmt.Sprintf("%s.retry_wait", c.configKey), persistentFlags.Lookup("retry-wait"))
+	viper.BindEnv(fmt.Sprintf("%s.retry_wait", c.configKey), fmt.Sprintf("%sRETRY_WAIT", envPrefix))
+
+	// template
+	persistentFlags.String("template", "",
+		"Go text/template used to format the command output (implies --output template)")
+	viper.BindPFlag(fmt.Sprintf("%s.template", c.configKey), persistentFlags.Lookup("template"))
+	viper.BindEnv(fmt.Sprintf("%s.template", c.configKey), fmt.Sprintf("%sTEMPLATE", envPrefix))
+
 	// tenant URL
 	persistentFlags.StringP("tenant-url", "t", "", "SentinelOne tenant URL")
 	viper.BindPFlag(fmt.Sprintf("%s.tenant_url", c.configKey), persistentFlags.Lookup("tenant-url"))
 	viper.BindEnv(fmt.Sprintf("%s.tenant_url", c.configKey), fmt.Sprintf("%sTENANT_URL", envPrefix))
+
+	// token expiry warning
+	persistentFlags.Duration("token-expiry-warning", _DefaultTokenExpiryWarning,
+		"warn when the API token expires within this amount of time")
+	viper.BindPFlag(fmt.Sprintf("%s.token_expiry_warning", c.configKey),
+		persistentFlags.Lookup("token-expiry-warning"))
+	viper.BindEnv(fmt.Sprintf("%s.token_expiry_warning", c.configKey),
+		fmt.Sprintf("%sTOKEN_EXPIRY_WARNING", envPrefix))
 }
 
 // ConfigKey returns the base name of the viper configuration key where the options are stored.
@@ -119,8 +239,43 @@ func (c *globalOptions) Load() errorx.Error {
 	// commands like 'configure' and 'version' do not require them and this could cause a failure here if
 	// they were required
 	c.APIKey = viperConfig.APIKey
+	c.Profile = viperConfig.Profile
 	c.TenantURL = viperConfig.TenantURL
 
+	// check output format
+	output := strings.ToLower(viperConfig.Output)
+	if output == OutputFormatTable && viperConfig.Template != "" {
+		output = OutputFormatTemplate
+	}
+	if !slices.Contains(OutputFormats, output) {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "output", viperConfig.Output,
+			fmt.Errorf("output format must be one of: %s", strings.Join(OutputFormats, ", ")))
+		logger.Error().
+			Err(errx).
+			Str("option", "output").
+			Str("value", viperConfig.Output).
+			Msg(errx.Error())
+		return errx
+	}
+	if output == OutputFormatTemplate {
+		tmpl, err := template.New("output").Funcs(outputTemplateFuncs).Parse(viperConfig.Template)
+		if err == nil && viperConfig.Template == "" {
+			err = goerrors.New("a template must be given when the output format is 'template'")
+		}
+		if err != nil {
+			errx := errors.NewConfigValidateFailure(c.ConfigFile, "template", viperConfig.Template, err)
+			logger.Error().
+				Err(errx).
+				Str("option", "template").
+				Str("value", viperConfig.Template).
+				Msg(errx.Error())
+			return errx
+		}
+		c.outputTemplate = tmpl
+	}
+	c.Output = output
+	c.Template = viperConfig.Template
+
 	// check log level
 	level, err := zerolog.ParseLevel(viperConfig.LogLevel)
 	if err != nil {
@@ -132,12 +287,93 @@ func (c *globalOptions) Load() errorx.Error {
 			Msg(errx.Error())
 		return errx
 	}
-	newLogger := logger.Level(level)
-	if level <= zerolog.DebugLevel {
-		newLogger = newLogger.With().Caller().Logger()
-	}
-	c.appState.logger = &newLogger
 	c.LogLevel = level
+	if c.Output == OutputFormatTable {
+		newLogger := logger.Level(level)
+		if level <= zerolog.DebugLevel {
+			newLogger = newLogger.With().Caller().Logger()
+		}
+		c.appState.logger = &newLogger
+	} else {
+		// keep stdout free for output meant for scripts
+		c.appState.initLogger(level)
+		logger = c.appState.logger
+	}
+
+	// check retry settings
+	if viperConfig.MaxRetries < 0 {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "max_retries", viperConfig.MaxRetries,
+			goerrors.New("maximum number of retries cannot be negative"))
+		logger.Error().
+			Err(errx).
+			Str("option", "max_retries").
+			Int("value", viperConfig.MaxRetries).
+			Msg(errx.Error())
+		return errx
+	}
+	if viperConfig.RetryWait < 0 || viperConfig.RetryMaxWait < viperConfig.RetryWait {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "retry_max_wait", viperConfig.RetryMaxWait.String(),
+			fmt.Errorf("retry wait times must be positive and the maximum wait must be at least %s",
+				viperConfig.RetryWait.String()))
+		logger.Error().
+			Err(errx).
+			Str("option", "retry_max_wait").
+			Str("value", viperConfig.RetryMaxWait.String()).
+			Msg(errx.Error())
+		return errx
+	}
+	if viperConfig.RequestTimeout < 0 {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "request_timeout", viperConfig.RequestTimeout.String(),
+			goerrors.New("request timeout cannot be negative"))
+		logger.Error().
+			Err(errx).
+			Str("option", "request_timeout").
+			Str("value", viperConfig.RequestTimeout.String()).
+			Msg(errx.Error())
+		return errx
+	}
+	if viperConfig.TokenExpiryWarning < 0 {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "token_expiry_warning",
+			viperConfig.TokenExpiryWarning.String(), goerrors.New("token expiry warning cannot be negative"))
+		logger.Error().
+			Err(errx).
+			Str("option", "token_expiry_warning").
+			Str("value", viperConfig.TokenExpiryWarning.String()).
+			Msg(errx.Error())
+		return errx
+	}
+	c.MaxRetries = viperConfig.MaxRetries
+	c.TokenExpiryWarning = viperConfig.TokenExpiryWarning
+
+	// check rate limits
+	if viperConfig.RateLimit < 0 {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "rate_limit", viperConfig.RateLimit,
+			goerrors.New("rate limit cannot be negative"))
+		logger.Error().
+			Err(errx).
+			Str("option", "rate_limit").
+			Float64("value", viperConfig.RateLimit).
+			Msg(errx.Error())
+		return errx
+	}
+	for endpoint, rate := range viperConfig.RateLimitOverrides {
+		if !strings.HasPrefix(endpoint, "/") || rate < 0 {
+			errx := errors.NewConfigValidateFailure(c.ConfigFile, "rate_limit_overrides", endpoint,
+				goerrors.New("endpoint must start with '/' and its rate limit cannot be negative"))
+			logger.Error().
+				Err(errx).
+				Str("option", "rate_limit_overrides").
+				Str("endpoint", endpoint).
+				Float64("value", rate).
+				Msg(errx.Error())
+			return errx
+		}
+	}
+	c.RateLimit = viperConfig.RateLimit
+	c.RateLimitOverrides = viperConfig.RateLimitOverrides
+	c.RequestTimeout = viperConfig.RequestTimeout
+	c.RetryMaxWait = viperConfig.RetryMaxWait
+	c.RetryWait = viperConfig.RetryWait
 
 	// save the absolute path to the directory in which the config file is located
 	absPath, err := filepath.Abs(c.ConfigFile)
@@ -155,6 +391,25 @@ func (c *globalOptions) Load() errorx.Error {
 		c.ConfigDir = filepath.Dir(absPath)
 	}
 
+	// keystore is kept in the user's configuration folder unless another location is given
+	keystoreFile := viperConfig.KeystoreFile
+	if keystoreFile == "" {
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			userConfigDir = c.ConfigDir
+		}
+		keystoreFile = filepath.Join(userConfigDir, build.AppCommand, _DefaultKeystoreFileName)
+	}
+	if c.KeystoreFile, err = filepath.Abs(os.ExpandEnv(keystoreFile)); err != nil {
+		errx := errors.NewConfigValidateFailure(c.ConfigFile, "keystore_file", keystoreFile, err)
+		logger.Error().
+			Err(errx).
+			Str("option", "keystore_file").
+			Str("value", keystoreFile).
+			Msg(errx.Error())
+		return errx
+	}
+
 	c.isLoaded = true
 	return nil
 }
@@ -170,6 +425,7 @@ func (c *globalOptions) LogSettings() {
 // Any errors returned by this function are a result of calling json.Marshal().
 func (c *globalOptions) MarshalJSON() ([]byte, error) {
 	cfg := jsonGlobalOptions(*c)
+	redactFields(&cfg)
 	return json.Marshal(&cfg)
 }
 
@@ -201,7 +457,18 @@ func (c *globalOptions) viperConfigFile() string {
 
 // viperGlobalOptions holds the global options for the root command.
 type viperGlobalOptions struct {
-	APIKey    string `mapstructure:"api_key"`
-	LogLevel  string `mapstructure:"log_level"`
-	TenantURL string `mapstructure:"tenant_url"`
+	APIKey             string             `mapstructure:"api_key"`
+	KeystoreFile       string             `mapstructure:"keystore_file"`
+	LogLevel           string             `mapstructure:"log_level"`
+	MaxRetries         int                `mapstructure:"max_retries"`
+	Output             string             `mapstructure:"output"`
+	Profile            string             `mapstructure:"profile"`
+	RateLimit          float64            `mapstructure:"rate_limit"`
+	RateLimitOverrides map[string]float64 `mapstructure:"rate_limit_overrides"`
+	RequestTimeout     time.Duration      `mapstructure:"request_timeout"`
+	RetryMaxWait       time.Duration      `mapstructure:"retry_max_wait"`
+	RetryWait          time.Duration      `mapstructure:"retry_wait"`
+	Template           string             `mapstructure:"template"`
+	TenantURL          string             `mapstructure:"tenant_url"`
+	TokenExpiryWarning time.Duration      `mapstructure:"token_expiry_warning"`
 }
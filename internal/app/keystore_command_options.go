@@ -0,0 +1,105 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+)
+
+// keystoreCommandOptions holds options for the 'keystore' subcommand and its subcommands.
+type keystoreCommandOptions struct {
+	// unexported variables
+	appState  *State
+	parent    *commandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonKeystoreCommandOptions is just an alias for keystoreCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonKeystoreCommandOptions keystoreCommandOptions
+
+// newKeystoreCommandOptions returns a new object with defaults set.
+func newKeystoreCommandOptions(state *State, parent *commandOptions) *keystoreCommandOptions {
+	configKey := _ConfigCommandKeystoreKey
+
+	return &keystoreCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *keystoreCommandOptions) BindFlags(cmd *cobra.Command) {
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *keystoreCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *keystoreCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *keystoreCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *keystoreCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'keystore' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *keystoreCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonKeystoreCommandOptions(*c)
+	//lint:ignore SA9005 this function may change in the future to export fields
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *keystoreCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *keystoreCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/jszwec/csvutil"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	OutputFormatCSV      = "csv"
+	OutputFormatJSON     = "json"
+	OutputFormatTable    = "table"
+	OutputFormatTemplate = "template"
+	OutputFormatYAML     = "yaml"
+)
+
+// OutputFormats holds the names of all of the supported output formats.
+var OutputFormats = []string{
+	OutputFormatCSV,
+	OutputFormatJSON,
+	OutputFormatTable,
+	OutputFormatTemplate,
+	OutputFormatYAML,
+}
+
+// outputTemplateFuncs holds the extra functions which may be used in output templates.
+var outputTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// Output writes the results of a command to stdout in the output format selected using the global options.
+//
+// Commands which have their own human-readable output should use it when IsTable() is true.
+type Output struct {
+	// unexported variables
+	appState *State
+	w        io.Writer
+}
+
+// Output returns the object used to write the results of a command.
+//
+// The global options must be loaded before any output is written.
+func (s *State) Output() *Output {
+	return &Output{
+		appState: s,
+		w:        os.Stdout,
+	}
+}
+
+// Format returns the selected output format.
+func (o *Output) Format() string {
+	return o.appState.config.globalOptions.Output
+}
+
+// IsTable returns whether or not output is meant for people rather than scripts.
+func (o *Output) IsTable() bool {
+	return o.Format() == OutputFormatTable
+}
+
+// Write writes the value in the selected output format.
+//
+// The value must be a struct or a slice of structs. The 'csv' tags of the struct fields name the columns of CSV and
+// table output while the 'json' and 'yaml' tags name the fields of JSON and YAML output. A single struct is shown as
+// a table of fields and values. Templates are given the value itself so fields are referred to by their Go names
+// (eg: {{range .}}{{.Name}}{{end}}).
+//
+// The following errors are returned by this function:
+// GeneralFailure
+func (o *Output) Write(v any) errorx.Error {
+	globalOpts := o.appState.config.globalOptions
+	var err error
+	switch globalOpts.Output {
+	case OutputFormatCSV:
+		var data []byte
+		if data, err = encodeCSV(v); err == nil {
+			_, err = o.w.Write(data)
+		}
+	case OutputFormatJSON:
+		var data []byte
+		if data, err = json.MarshalIndent(v, "", "  "); err == nil {
+			_, err = o.w.Write(append(data, '\n'))
+		}
+	case OutputFormatTemplate:
+		buf := &bytes.Buffer{}
+		if err = globalOpts.outputTemplate.Execute(buf, v); err == nil {
+			if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteByte('\n')
+			}
+			_, err = o.w.Write(buf.Bytes())
+		}
+	case OutputFormatYAML:
+		enc := yaml.NewEncoder(o.w)
+		enc.SetIndent(2)
+		if err = enc.Encode(v); err == nil {
+			err = enc.Close()
+		}
+	default:
+		err = writeTable(o.w, v)
+	}
+	if err != nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("failed to write %s output", globalOpts.Output), err)
+		o.appState.logger.Error().Err(errx).Str("output", globalOpts.Output).Msg(errx.Error())
+		return errx
+	}
+	return nil
+}
+
+// encodeCSV converts the value to CSV including a header row.
+//
+// A single struct is written as a single row.
+func encodeCSV(v any) ([]byte, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Slice {
+		slice := reflect.MakeSlice(reflect.SliceOf(rv.Type()), 1, 1)
+		slice.Index(0).Set(rv)
+		v = slice.Interface()
+	}
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := csvutil.NewEncoder(w).Encode(v); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeTable writes the value as a table with aligned columns.
+//
+// The headings are the CSV column names in upper case with underscores replaced by spaces. A single struct is
+// written as one row per field.
+func writeTable(w io.Writer, v any) error {
+	data, err := encodeCSV(v)
+	if err != nil {
+		return err
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		for i := range rows[0] {
+			rows[0][i] = strings.ToUpper(strings.ReplaceAll(rows[0][i], "_", " "))
+		}
+	}
+	if reflect.ValueOf(v).Kind() != reflect.Slice && len(rows) == 2 {
+		fields := make([][]string, 0, len(rows[0]))
+		for i := range rows[0] {
+			fields = append(fields, []string{rows[0][i] + ":", rows[1][i]})
+		}
+		rows = fields
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -5,6 +5,7 @@ import (
 	goerrors "errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -16,10 +17,36 @@ import (
 
 // provisionAccountCommandOptions holds options for the 'provision account' subcommand.
 type provisionAccountCommandOptions struct {
-	CSVSeparator             string `json:"csv_separator"`
-	CSVSource                string `json:"csv_source"`
-	ReactivateExpiredAccount bool   `json:"reactivate_expired_account"`
-	ResetFirstUserPassword   bool   `json:"reset_first_user_password"`
+	AccountName              string            `json:"account_name"`
+	AccountNameFormat        string            `json:"account_name_format"`
+	AccountType              string            `json:"account_type"`
+	AssumeYes                bool              `json:"assume_yes"`
+	BillingMode              string            `json:"billing_mode"`
+	Bundle                   string            `json:"bundle"`
+	Bundles                  string            `json:"bundles"`
+	Concurrency              int               `json:"concurrency"`
+	ContinueOnError          bool              `json:"continue_on_error"`
+	CredentialsFile          string            `json:"credentials_file"`
+	CSVSeparator             string            `json:"csv_separator"`
+	CSVSource                string            `json:"csv_source"`
+	DryRun                   bool              `json:"dry_run"`
+	EmailAddress             string            `json:"email_address"`
+	Expires                  string            `json:"expires"`
+	ExternalID               string            `json:"external_id"`
+	FirstName                string            `json:"first_name"`
+	Inherits                 bool              `json:"inherits"`
+	LastName                 string            `json:"last_name"`
+	LicenseSettings          map[string]string `json:"license_settings"`
+	Modules                  string            `json:"modules"`
+	PasswordClasses          string            `json:"password_classes"`
+	PasswordLength           int               `json:"password_length"`
+	ReactivateExpiredAccount bool              `json:"reactivate_expired_account"`
+	ResetFirstUserPassword   bool              `json:"reset_first_user_password"`
+	ResultsFile              string            `json:"results_file"`
+	ResultsFormat            string            `json:"results_format"`
+	Role                     string            `json:"role"`
+	TotalAgents              int               `json:"total_agents"`
+	UsageType                string            `json:"usage_type"`
 
 	// unexported variables
 	appState  *State
@@ -37,12 +64,39 @@ func newProvisionAccountCommandOptions(state *State,
 	parent *provisionCommandOptions) *provisionAccountCommandOptions {
 
 	configKey := _ConfigCommandProvisionAccountKey
+	viper.SetDefault(fmt.Sprintf("%s.account_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_name_format", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_type", configKey), _DefaultAccountType)
+	viper.SetDefault(fmt.Sprintf("%s.assume_yes", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.billing_mode", configKey), _DefaultBillingMode)
+	viper.SetDefault(fmt.Sprintf("%s.bundle", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.bundles", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.concurrency", configKey), _DefaultProvisionConcurrency)
+	viper.SetDefault(fmt.Sprintf("%s.continue_on_error", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.credentials_file", configKey), "")
 	viper.SetDefault(fmt.Sprintf("%s.csv_separator", configKey), _DefaultCSVSeparator)
 	viper.SetDefault(fmt.Sprintf("%s.csv_source", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.dry_run", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.email_address", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.expires", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.external_id", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.first_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.inherits", configKey), true)
+	viper.SetDefault(fmt.Sprintf("%s.last_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.license_settings", configKey), _DefaultLicenseSettings)
+	viper.SetDefault(fmt.Sprintf("%s.modules", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.password_classes", configKey), _DefaultPasswordClasses)
+	viper.SetDefault(fmt.Sprintf("%s.password_length", configKey), _DefaultPasswordLength)
 	viper.SetDefault(fmt.Sprintf("%s.reactivate_expired_account", configKey), false)
 	viper.SetDefault(fmt.Sprintf("%s.reset_first_user_password", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.results_file", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.results_format", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.role", configKey), _DefaultUserRole)
+	viper.SetDefault(fmt.Sprintf("%s.total_agents", configKey), 0)
+	viper.SetDefault(fmt.Sprintf("%s.usage_type", configKey), _DefaultUsageType)
 
 	return &provisionAccountCommandOptions{
+		Concurrency:  _DefaultProvisionConcurrency,
 		CSVSeparator: _DefaultCSVSeparator,
 		appState:     state,
 		parent:       parent,
@@ -55,6 +109,57 @@ func (c *provisionAccountCommandOptions) BindFlags(cmd *cobra.Command) {
 	flags := cmd.Flags()
 	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
 
+	// --account-name
+	flags.String("account-name", "", "name of the account to provision")
+	viper.BindPFlag(fmt.Sprintf("%s.account_name", c.configKey), flags.Lookup("account-name"))
+	viper.BindEnv(fmt.Sprintf("%s.account_name", c.configKey), fmt.Sprintf("%sACCOUNT_NAME", envPrefix))
+
+	// --account-name-format
+	flags.String("account-name-format", "",
+		"when using a CSV, Go template used to name accounts without an account_name "+
+			"(eg: {{.company}} {{seq | pad 3}})")
+	viper.BindPFlag(fmt.Sprintf("%s.account_name_format", c.configKey), flags.Lookup("account-name-format"))
+	viper.BindEnv(fmt.Sprintf("%s.account_name_format", c.configKey),
+		fmt.Sprintf("%sACCOUNT_NAME_FORMAT", envPrefix))
+
+	// --account-type
+	flags.String("account-type", _DefaultAccountType,
+		"type of account to provision, also used for CSV rows without one: Trial or Paid")
+	viper.BindPFlag(fmt.Sprintf("%s.account_type", c.configKey), flags.Lookup("account-type"))
+	viper.BindEnv(fmt.Sprintf("%s.account_type", c.configKey), fmt.Sprintf("%sACCOUNT_TYPE", envPrefix))
+
+	// --billing-mode
+	flags.String("billing-mode", _DefaultBillingMode, "billing mode of the account")
+	viper.BindPFlag(fmt.Sprintf("%s.billing_mode", c.configKey), flags.Lookup("billing-mode"))
+	viper.BindEnv(fmt.Sprintf("%s.billing_mode", c.configKey), fmt.Sprintf("%sBILLING_MODE", envPrefix))
+
+	// --bundle
+	flags.String("bundle", "", "name of the license bundle for the account")
+	viper.BindPFlag(fmt.Sprintf("%s.bundle", c.configKey), flags.Lookup("bundle"))
+	viper.BindEnv(fmt.Sprintf("%s.bundle", c.configKey), fmt.Sprintf("%sBUNDLE", envPrefix))
+
+	// --bundles
+	flags.String("bundles", "",
+		"license bundles and their surfaces in place of --bundle and --total-agents (eg: complete:Total Agents=10)")
+	viper.BindPFlag(fmt.Sprintf("%s.bundles", c.configKey), flags.Lookup("bundles"))
+	viper.BindEnv(fmt.Sprintf("%s.bundles", c.configKey), fmt.Sprintf("%sBUNDLES", envPrefix))
+
+	// --concurrency
+	flags.Int("concurrency", _DefaultProvisionConcurrency, "number of accounts to provision at the same time")
+	viper.BindPFlag(fmt.Sprintf("%s.concurrency", c.configKey), flags.Lookup("concurrency"))
+	viper.BindEnv(fmt.Sprintf("%s.concurrency", c.configKey), fmt.Sprintf("%sCONCURRENCY", envPrefix))
+
+	// --continue-on-error
+	flags.Bool("continue-on-error", false, "keep provisioning the remaining accounts when an account fails")
+	viper.BindPFlag(fmt.Sprintf("%s.continue_on_error", c.configKey), flags.Lookup("continue-on-error"))
+	viper.BindEnv(fmt.Sprintf("%s.continue_on_error", c.configKey), fmt.Sprintf("%sCONTINUE_ON_ERROR", envPrefix))
+
+	// --credentials-file
+	flags.String("credentials-file", "",
+		"save the passwords generated for new users to the given encrypted keystore file")
+	viper.BindPFlag(fmt.Sprintf("%s.credentials_file", c.configKey), flags.Lookup("credentials-file"))
+	viper.BindEnv(fmt.Sprintf("%s.credentials_file", c.configKey), fmt.Sprintf("%sCREDENTIALS_FILE", envPrefix))
+
 	// --csv-separator
 	flags.String("csv-separator", _DefaultCSVSeparator, "when using a CSV, this is the separator token")
 	viper.BindPFlag(fmt.Sprintf("%s.csv_separator", c.configKey), flags.Lookup("csv-separator"))
@@ -65,6 +170,62 @@ func (c *provisionAccountCommandOptions) BindFlags(cmd *cobra.Command) {
 	viper.BindPFlag(fmt.Sprintf("%s.csv_source", c.configKey), flags.Lookup("csv-source"))
 	viper.BindEnv(fmt.Sprintf("%s.csv_source", c.configKey), fmt.Sprintf("%sCSV_SOURCE", envPrefix))
 
+	// --dry-run
+	flags.Bool("dry-run", false, "show what would be provisioned without making any changes")
+	viper.BindPFlag(fmt.Sprintf("%s.dry_run", c.configKey), flags.Lookup("dry-run"))
+	viper.BindEnv(fmt.Sprintf("%s.dry_run", c.configKey), fmt.Sprintf("%sDRY_RUN", envPrefix))
+
+	// --email-address
+	flags.String("email-address", "", "e-mail address of the account's first user")
+	viper.BindPFlag(fmt.Sprintf("%s.email_address", c.configKey), flags.Lookup("email-address"))
+	viper.BindEnv(fmt.Sprintf("%s.email_address", c.configKey), fmt.Sprintf("%sEMAIL_ADDRESS", envPrefix))
+
+	// --expires
+	flags.String("expires", "", "when the account expires as a duration (eg: 72h) or RFC3339 date")
+	viper.BindPFlag(fmt.Sprintf("%s.expires", c.configKey), flags.Lookup("expires"))
+	viper.BindEnv(fmt.Sprintf("%s.expires", c.configKey), fmt.Sprintf("%sEXPIRES", envPrefix))
+
+	// --external-id
+	flags.String("external-id", "", "external ID for the account")
+	viper.BindPFlag(fmt.Sprintf("%s.external_id", c.configKey), flags.Lookup("external-id"))
+	viper.BindEnv(fmt.Sprintf("%s.external_id", c.configKey), fmt.Sprintf("%sEXTERNAL_ID", envPrefix))
+
+	// --first-name
+	flags.String("first-name", "", "first name of the account's first user")
+	viper.BindPFlag(fmt.Sprintf("%s.first_name", c.configKey), flags.Lookup("first-name"))
+	viper.BindEnv(fmt.Sprintf("%s.first_name", c.configKey), fmt.Sprintf("%sFIRST_NAME", envPrefix))
+
+	// --inherits
+	flags.Bool("inherits", true, "whether the account inherits its settings from the tenant")
+	viper.BindPFlag(fmt.Sprintf("%s.inherits", c.configKey), flags.Lookup("inherits"))
+	viper.BindEnv(fmt.Sprintf("%s.inherits", c.configKey), fmt.Sprintf("%sINHERITS", envPrefix))
+
+	// --last-name
+	flags.String("last-name", "", "last name of the account's first user")
+	viper.BindPFlag(fmt.Sprintf("%s.last_name", c.configKey), flags.Lookup("last-name"))
+	viper.BindEnv(fmt.Sprintf("%s.last_name", c.configKey), fmt.Sprintf("%sLAST_NAME", envPrefix))
+
+	// --license-setting
+	flags.StringToString("license-setting", nil,
+		"license setting for the account as group=setting; may be repeated and replaces the default settings")
+	viper.BindPFlag(fmt.Sprintf("%s.license_settings", c.configKey), flags.Lookup("license-setting"))
+
+	// --modules
+	flags.String("modules", "", "comma-separated list of license modules for the account")
+	viper.BindPFlag(fmt.Sprintf("%s.modules", c.configKey), flags.Lookup("modules"))
+	viper.BindEnv(fmt.Sprintf("%s.modules", c.configKey), fmt.Sprintf("%sMODULES", envPrefix))
+
+	// --password-classes
+	flags.String("password-classes", _DefaultPasswordClasses,
+		"comma-separated list of character classes required in the passwords of new users")
+	viper.BindPFlag(fmt.Sprintf("%s.password_classes", c.configKey), flags.Lookup("password-classes"))
+	viper.BindEnv(fmt.Sprintf("%s.password_classes", c.configKey), fmt.Sprintf("%sPASSWORD_CLASSES", envPrefix))
+
+	// --password-length
+	flags.Int("password-length", _DefaultPasswordLength, "number of characters in the passwords of new users")
+	viper.BindPFlag(fmt.Sprintf("%s.password_length", c.configKey), flags.Lookup("password-length"))
+	viper.BindEnv(fmt.Sprintf("%s.password_length", c.configKey), fmt.Sprintf("%sPASSWORD_LENGTH", envPrefix))
+
 	// --reactivate-expired-account
 	flags.Bool("reactivate-expired-account", false, "if an account exists and is expired, reactivate it")
 	viper.BindPFlag(fmt.Sprintf("%s.reactivate_expired_account", c.configKey),
@@ -73,11 +234,43 @@ func (c *provisionAccountCommandOptions) BindFlags(cmd *cobra.Command) {
 		fmt.Sprintf("%sREACTIVATE_EXPIRED_ACCOUNT", envPrefix))
 
 	// --reset-first-user-password
-	flags.Bool("reset-first-user-password", false, "send the first user a password reset email")
+	flags.Bool("reset-first-user-password", false,
+		"send each user a password reset email unless their reset_password column says otherwise")
 	viper.BindPFlag(fmt.Sprintf("%s.reset_first_user_password", c.configKey),
 		flags.Lookup("reset-first-user-password"))
 	viper.BindEnv(fmt.Sprintf("%s.reset_first_user_password", c.configKey),
 		fmt.Sprintf("%sRESET_FIRST_USER_PASSWORD", envPrefix))
+
+	// --results-file
+	flags.String("results-file", "", "write the result of provisioning each account to the given file")
+	viper.BindPFlag(fmt.Sprintf("%s.results_file", c.configKey), flags.Lookup("results-file"))
+	viper.BindEnv(fmt.Sprintf("%s.results_file", c.configKey), fmt.Sprintf("%sRESULTS_FILE", envPrefix))
+
+	// --results-format
+	flags.String("results-format", "", "format of the results file: csv or json (default based on file extension)")
+	viper.BindPFlag(fmt.Sprintf("%s.results_format", c.configKey), flags.Lookup("results-format"))
+	viper.BindEnv(fmt.Sprintf("%s.results_format", c.configKey), fmt.Sprintf("%sRESULTS_FORMAT", envPrefix))
+
+	// --role
+	flags.String("role", _DefaultUserRole,
+		"name or ID of the role given to the account's user, also used for CSV rows without one")
+	viper.BindPFlag(fmt.Sprintf("%s.role", c.configKey), flags.Lookup("role"))
+	viper.BindEnv(fmt.Sprintf("%s.role", c.configKey), fmt.Sprintf("%sROLE", envPrefix))
+
+	// --total-agents
+	flags.Int("total-agents", 0, "number of agents licensed for the account")
+	viper.BindPFlag(fmt.Sprintf("%s.total_agents", c.configKey), flags.Lookup("total-agents"))
+	viper.BindEnv(fmt.Sprintf("%s.total_agents", c.configKey), fmt.Sprintf("%sTOTAL_AGENTS", envPrefix))
+
+	// --usage-type
+	flags.String("usage-type", _DefaultUsageType, "usage type of the account")
+	viper.BindPFlag(fmt.Sprintf("%s.usage_type", c.configKey), flags.Lookup("usage-type"))
+	viper.BindEnv(fmt.Sprintf("%s.usage_type", c.configKey), fmt.Sprintf("%sUSAGE_TYPE", envPrefix))
+
+	// --yes
+	flags.BoolP("yes", "y", false, "provision the account without asking for confirmation")
+	viper.BindPFlag(fmt.Sprintf("%s.assume_yes", c.configKey), flags.Lookup("yes"))
+	viper.BindEnv(fmt.Sprintf("%s.assume_yes", c.configKey), fmt.Sprintf("%sASSUME_YES", envPrefix))
 }
 
 // ConfigKey returns the base name of the viper configuration key where the options are stored.
@@ -85,6 +278,23 @@ func (c *provisionAccountCommandOptions) ConfigKey() string {
 	return c.configKey
 }
 
+// Defaults returns the options as they are configured without validating them.
+//
+// This allows the configured settings to be offered as defaults (eg: by the configure command) even when some of
+// them are not valid. If the options have already been loaded, the loaded options are returned.
+func (c *provisionAccountCommandOptions) Defaults() *provisionAccountCommandOptions {
+	if c.isLoaded {
+		return c
+	}
+	opts := &provisionAccountCommandOptions{
+		appState:  c.appState,
+		parent:    c.parent,
+		configKey: c.configKey,
+	}
+	opts.save(&c.appState.config.viperConfig.CommandOptions.Provision.Account)
+	return opts
+}
+
 // IsLoaded returns whether or not the configuration settings have been loaded.
 func (c *provisionAccountCommandOptions) IsLoaded() bool {
 	return c.isLoaded
@@ -107,6 +317,30 @@ func (c *provisionAccountCommandOptions) Load() errorx.Error {
 	viperConfig := c.appState.config.viperConfig.CommandOptions.Provision.Account
 	logger := c.appState.logger
 
+	// must provision at least 1 account at a time
+	if err := ValidateProvisionConcurrency(viperConfig.Concurrency); err != nil {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "concurrency",
+			viperConfig.Concurrency, err)
+		logger.Error().
+			Err(errx).
+			Str("option", "concurrency").
+			Int("value", viperConfig.Concurrency).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// passwords must be long enough to be accepted by the S1 API
+	if viperConfig.PasswordLength < _MinPasswordLength {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "password_length",
+			viperConfig.PasswordLength, fmt.Errorf("password length must be at least %d", _MinPasswordLength))
+		logger.Error().
+			Err(errx).
+			Str("option", "password_length").
+			Int("value", viperConfig.PasswordLength).
+			Msg(errx.Error())
+		return errx
+	}
+
 	// using a CSV file
 	if viperConfig.CSVSource != "" {
 		// CSV separator cannot be empty
@@ -147,11 +381,61 @@ func (c *provisionAccountCommandOptions) Load() errorx.Error {
 		}
 	}
 
+	// determine the format of the results file
+	if viperConfig.ResultsFormat == "" {
+		viperConfig.ResultsFormat = _ResultsFormatCSV
+		if strings.EqualFold(filepath.Ext(viperConfig.ResultsFile), ".json") {
+			viperConfig.ResultsFormat = _ResultsFormatJSON
+		}
+	}
+	viperConfig.ResultsFormat = strings.ToLower(viperConfig.ResultsFormat)
+	if viperConfig.ResultsFormat != _ResultsFormatCSV && viperConfig.ResultsFormat != _ResultsFormatJSON {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "results_format",
+			viperConfig.ResultsFormat, goerrors.New("results format must be either csv or json"))
+		logger.Error().
+			Err(errx).
+			Str("option", "results_format").
+			Str("value", viperConfig.ResultsFormat).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// number of agents cannot be negative
+	if viperConfig.TotalAgents < 0 {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "total_agents",
+			viperConfig.TotalAgents, goerrors.New("total number of agents cannot be negative"))
+		logger.Error().
+			Err(errx).
+			Str("option", "total_agents").
+			Int("value", viperConfig.TotalAgents).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// billing mode and usage type are required by the S1 API
+	if viperConfig.BillingMode == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "billing_mode",
+			viperConfig.BillingMode, goerrors.New("billing mode cannot be empty"))
+		logger.Error().
+			Err(errx).
+			Str("option", "billing_mode").
+			Str("value", viperConfig.BillingMode).
+			Msg(errx.Error())
+		return errx
+	}
+	if viperConfig.UsageType == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "usage_type",
+			viperConfig.UsageType, goerrors.New("usage type cannot be empty"))
+		logger.Error().
+			Err(errx).
+			Str("option", "usage_type").
+			Str("value", viperConfig.UsageType).
+			Msg(errx.Error())
+		return errx
+	}
+
 	// save options
-	c.CSVSeparator = viperConfig.CSVSeparator
-	c.CSVSource = viperConfig.CSVSource
-	c.ReactivateExpiredAccount = viperConfig.ReactivateExpiredAccount
-	c.ResetFirstUserPassword = viperConfig.ResetFirstUserPassword
+	c.save(&viperConfig)
 
 	c.isLoaded = true
 	return nil
@@ -195,10 +479,70 @@ func (c *provisionAccountCommandOptions) String() string {
 	return string(output)
 }
 
+// save copies the settings read by viper into the object.
+func (c *provisionAccountCommandOptions) save(viperConfig *viperProvisionAccountCommandOptions) {
+	c.AccountName = viperConfig.AccountName
+	c.AccountNameFormat = viperConfig.AccountNameFormat
+	c.AccountType = viperConfig.AccountType
+	c.AssumeYes = viperConfig.AssumeYes
+	c.BillingMode = viperConfig.BillingMode
+	c.Bundle = viperConfig.Bundle
+	c.Bundles = viperConfig.Bundles
+	c.Concurrency = viperConfig.Concurrency
+	c.ContinueOnError = viperConfig.ContinueOnError
+	c.CredentialsFile = viperConfig.CredentialsFile
+	c.CSVSeparator = viperConfig.CSVSeparator
+	c.CSVSource = viperConfig.CSVSource
+	c.DryRun = viperConfig.DryRun
+	c.EmailAddress = viperConfig.EmailAddress
+	c.Expires = viperConfig.Expires
+	c.ExternalID = viperConfig.ExternalID
+	c.FirstName = viperConfig.FirstName
+	c.Inherits = viperConfig.Inherits
+	c.LastName = viperConfig.LastName
+	c.LicenseSettings = viperConfig.LicenseSettings
+	c.Modules = viperConfig.Modules
+	c.PasswordClasses = viperConfig.PasswordClasses
+	c.PasswordLength = viperConfig.PasswordLength
+	c.ReactivateExpiredAccount = viperConfig.ReactivateExpiredAccount
+	c.ResetFirstUserPassword = viperConfig.ResetFirstUserPassword
+	c.ResultsFile = viperConfig.ResultsFile
+	c.ResultsFormat = viperConfig.ResultsFormat
+	c.Role = viperConfig.Role
+	c.TotalAgents = viperConfig.TotalAgents
+	c.UsageType = viperConfig.UsageType
+}
+
 // viperProvisionAccouintCommandOptions holds the options for the 'provision account' subcommand.
 type viperProvisionAccountCommandOptions struct {
-	CSVSeparator             string `mapstructure:"csv_separator"`
-	CSVSource                string `mapstructure:"csv_source"`
-	ReactivateExpiredAccount bool   `mapstructure:"reactivate_expired_account"`
-	ResetFirstUserPassword   bool   `mapstructure:"reset_first_user_password"`
+	AccountName              string            `mapstructure:"account_name"`
+	AccountNameFormat        string            `mapstructure:"account_name_format"`
+	AccountType              string            `mapstructure:"account_type"`
+	AssumeYes                bool              `mapstructure:"assume_yes"`
+	BillingMode              string            `mapstructure:"billing_mode"`
+	Bundle                   string            `mapstructure:"bundle"`
+	Bundles                  string            `mapstructure:"bundles"`
+	Concurrency              int               `mapstructure:"concurrency"`
+	ContinueOnError          bool              `mapstructure:"continue_on_error"`
+	CredentialsFile          string            `mapstructure:"credentials_file"`
+	CSVSeparator             string            `mapstructure:"csv_separator"`
+	CSVSource                string            `mapstructure:"csv_source"`
+	DryRun                   bool              `mapstructure:"dry_run"`
+	EmailAddress             string            `mapstructure:"email_address"`
+	Expires                  string            `mapstructure:"expires"`
+	ExternalID               string            `mapstructure:"external_id"`
+	FirstName                string            `mapstructure:"first_name"`
+	Inherits                 bool              `mapstructure:"inherits"`
+	LastName                 string            `mapstructure:"last_name"`
+	LicenseSettings          map[string]string `mapstructure:"license_settings"`
+	Modules                  string            `mapstructure:"modules"`
+	PasswordClasses          string            `mapstructure:"password_classes"`
+	PasswordLength           int               `mapstructure:"password_length"`
+	ReactivateExpiredAccount bool              `mapstructure:"reactivate_expired_account"`
+	ResetFirstUserPassword   bool              `mapstructure:"reset_first_user_password"`
+	ResultsFile              string            `mapstructure:"results_file"`
+	ResultsFormat            string            `mapstructure:"results_format"`
+	Role                     string            `mapstructure:"role"`
+	TotalAgents              int               `mapstructure:"total_agents"`
+	UsageType                string            `mapstructure:"usage_type"`
 }
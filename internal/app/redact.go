@@ -0,0 +1,42 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.joshhogle.dev/s1cli/internal/secrets"
+)
+
+// redactFields masks the value of each field of the given struct which is tagged with `redact:"true"`.
+//
+// The struct must be passed as a pointer and only string fields are masked. Values which only refer to where a secret
+// is stored (eg: keystore://prod) are not secret themselves and are left as-is so that it is still possible to tell
+// where the secret came from. The exception is a cmd:// reference since the command itself may hold a secret (eg:
+// cmd://echo my-api-key), so only its scheme is kept.
+//
+// This is called on a copy of any options object holding secrets when it is marshalled so that secrets never end up
+// in logs or other output.
+func redactFields(v any) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() || field.Tag.Get("redact") != "true" {
+			continue
+		}
+		value := rv.Field(i)
+		if value.Kind() != reflect.String || value.String() == "" {
+			continue
+		}
+		switch scheme := secrets.Scheme(value.String()); scheme {
+		case "":
+			value.SetString(_RedactedValue)
+		case "cmd":
+			value.SetString(fmt.Sprintf("%s://%s", scheme, _RedactedValue))
+		}
+	}
+}
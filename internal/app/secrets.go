@@ -0,0 +1,265 @@
+package app
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/keystore"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+	"go.joshhogle.dev/s1cli/internal/secrets"
+)
+
+// CheckAPIKeyUpdatable returns an error if UpdateAPIKey() would be unable to replace the API key.
+//
+// This is used to make sure there is somewhere to keep a new API key before it is generated.
+//
+// The following errors are returned by this function:
+// ConfigLoadFailure, KeystoreFailure, SecretUpdateFailure
+func (c *globalOptions) CheckAPIKeyUpdatable() errorx.Error {
+	_, errx := c.apiKeyUpdater()
+	return errx
+}
+
+// OpenKeystore unlocks the keystore, creating a new empty keystore if the file does not exist yet.
+//
+// The keystore is only unlocked once. Later calls return the same keystore.
+//
+// The passphrase is read from the <PREFIX>KEYSTORE_PASSPHRASE environment variable. If it is not set, the user is
+// asked for it when running in a terminal.
+//
+// The following errors are returned by this function:
+// KeystoreFailure
+func (c *globalOptions) OpenKeystore() (*keystore.Keystore, errorx.Error) {
+	if c.keystore != nil {
+		return c.keystore, nil
+	}
+	ks, errx := c.OpenKeystoreFile(c.KeystoreFile)
+	if errx != nil {
+		return nil, errx
+	}
+	c.keystore = ks
+	return ks, nil
+}
+
+// OpenKeystoreFile unlocks the keystore in the given file, creating a new empty keystore if the file does not exist
+// yet.
+//
+// This is used for keystores other than the one holding the application's secrets. The passphrase is read the same
+// way as for OpenKeystore().
+//
+// The following errors are returned by this function:
+// KeystoreFailure
+func (c *globalOptions) OpenKeystoreFile(file string) (*keystore.Keystore, errorx.Error) {
+	logger := c.appState.Logger().With().Str("keystore_file", file).Logger()
+
+	_, err := os.Stat(file)
+	passphrase, err := c.keystorePassphrase(goerrors.Is(err, os.ErrNotExist))
+	if err != nil {
+		errx := errors.NewKeystoreFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	ks, err := keystore.Open(file, passphrase)
+	if err != nil {
+		errx := errors.NewKeystoreFailure(file, err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	return ks, nil
+}
+
+// ResolveAPIKey returns the API key, reading it from wherever it is stored if the setting is a reference.
+//
+// The key is only looked up once. Later calls return the same key.
+//
+// The following errors are returned by this function:
+// SecretResolveFailure
+func (c *globalOptions) ResolveAPIKey() (string, errorx.Error) {
+	if c.resolvedAPIKey != "" {
+		return c.resolvedAPIKey, nil
+	}
+	apiKey, errx := c.ResolveSecret("api_key", c.APIKey)
+	if errx != nil {
+		return "", errx
+	}
+	c.resolvedAPIKey = apiKey
+	return apiKey, nil
+}
+
+// ResolveSecret returns the secret for the given setting value.
+//
+// The value may refer to a file (file://path), an environment variable (env://NAME), the output of a command
+// (cmd://command) or an entry in the keystore (keystore://name). Any other value is returned unchanged.
+//
+// The following errors are returned by this function:
+// SecretResolveFailure
+func (c *globalOptions) ResolveSecret(setting, value string) (string, errorx.Error) {
+	if c.secrets == nil {
+		c.secrets = secrets.NewResolver(
+			secrets.CommandSource{},
+			secrets.EnvSource{},
+			secrets.FileSource{},
+			&secrets.KeystoreSource{
+				OpenFn: func() (*keystore.Keystore, error) {
+					ks, errx := c.OpenKeystore()
+					if errx != nil {
+						return nil, errx
+					}
+					return ks, nil
+				},
+			},
+		)
+	}
+	secret, err := c.secrets.Resolve(value)
+	if err != nil {
+		errx := errors.NewSecretResolveFailure(setting, err)
+		c.appState.Logger().Error().Err(errx).Str("setting", setting).Str("scheme", secrets.Scheme(value)).
+			Msg(errx.Error())
+		return "", errx
+	}
+	return secret, nil
+}
+
+// UpdateAPIKey replaces the API key where it is stored and returns a description of where that is.
+//
+// If the api_key setting refers to the keystore (keystore://name) or a file (file://path), the key is replaced there
+// and the configuration file is left unchanged. Otherwise the key is replaced in the configuration file, either in
+// the selected profile or in the global settings, depending on where it was read from. Keys read from environment
+// variables (env://NAME) or commands (cmd://command) cannot be replaced.
+//
+// The following errors are returned by this function:
+// ConfigLoadFailure, ConfigWriteFailure, KeystoreFailure, SecretUpdateFailure
+func (c *globalOptions) UpdateAPIKey(apiKey string) (string, errorx.Error) {
+	updateFn, errx := c.apiKeyUpdater()
+	if errx != nil {
+		return "", errx
+	}
+	location, errx := updateFn(apiKey)
+	if errx != nil {
+		return "", errx
+	}
+	c.resolvedAPIKey = apiKey
+	return location, nil
+}
+
+// apiKeyPath returns the keys leading to the api_key setting in the configuration file.
+//
+// When a profile is selected, the key belongs to the first profile in the chain of inherited profiles which sets it.
+func (c *globalOptions) apiKeyPath() []string {
+	seen := map[string]bool{}
+	for name := strings.ToLower(c.Profile); name != "" && !seen[name]; {
+		seen[name] = true
+		profile := viper.GetStringMap(fmt.Sprintf("%s.%s", _ConfigProfilesKey, name))
+		if _, ok := profile["api_key"]; ok {
+			return []string{_ConfigProfilesKey, name, "api_key"}
+		}
+		parent, _ := profile[_ProfileInheritsKey].(string)
+		name = strings.ToLower(parent)
+	}
+	return []string{c.configKey, "api_key"}
+}
+
+// apiKeyUpdater returns a function which replaces the API key where it is stored.
+//
+// Any checks which can be made before the key is replaced are made here so that problems are found early.
+func (c *globalOptions) apiKeyUpdater() (func(string) (string, errorx.Error), errorx.Error) {
+	logger := c.appState.Logger().With().Str("setting", "api_key").Logger()
+	scheme := secrets.Scheme(c.APIKey)
+	ref := strings.TrimPrefix(c.APIKey, scheme+"://")
+
+	switch scheme {
+	case "keystore":
+		ks, errx := c.OpenKeystore()
+		if errx != nil {
+			return nil, errx
+		}
+		return func(apiKey string) (string, errorx.Error) {
+			err := ks.Set(ref, apiKey)
+			if err == nil {
+				err = ks.Save()
+			}
+			if err != nil {
+				errx := errors.NewKeystoreFailure(ks.File(), err)
+				logger.Error().Err(errx).Msg(errx.Error())
+				return "", errx
+			}
+			return fmt.Sprintf("'%s' in keystore '%s'", ref, ks.File()), nil
+		}, nil
+
+	case "file":
+		file := os.ExpandEnv(ref)
+		info, err := os.Stat(file)
+		if err != nil {
+			errx := errors.NewSecretUpdateFailure("api_key", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return nil, errx
+		}
+		return func(apiKey string) (string, errorx.Error) {
+			if err := os.WriteFile(file, []byte(apiKey+"\n"), info.Mode().Perm()); err != nil {
+				errx := errors.NewSecretUpdateFailure("api_key", err)
+				logger.Error().Err(errx).Msg(errx.Error())
+				return "", errx
+			}
+			return fmt.Sprintf("file '%s'", file), nil
+		}, nil
+
+	case "":
+		// make sure the key being used is the one in the file and not one given by a flag or environment variable
+		path := c.apiKeyPath()
+		current, errx := c.parent.fileSetting(path)
+		if errx != nil {
+			return nil, errx
+		}
+		if current != c.APIKey {
+			errx := errors.NewSecretUpdateFailure("api_key", fmt.Errorf(
+				"the API key being used is not the one in '%s' of '%s'; was it set using a flag or environment variable?",
+				strings.Join(path, "."), c.ConfigFile))
+			logger.Error().Err(errx).Msg(errx.Error())
+			return nil, errx
+		}
+		return func(apiKey string) (string, errorx.Error) {
+			if errx := c.parent.updateFileSetting(path, apiKey); errx != nil {
+				return "", errx
+			}
+			return fmt.Sprintf("'%s' in '%s'", strings.Join(path, "."), c.ConfigFile), nil
+		}, nil
+	}
+
+	errx := errors.NewSecretUpdateFailure("api_key", fmt.Errorf("keys read using %s:// references cannot be replaced",
+		scheme))
+	logger.Error().Err(errx).Msg(errx.Error())
+	return nil, errx
+}
+
+// keystorePassphrase returns the passphrase used to unlock the keystore.
+//
+// When asking the user for the passphrase of a new keystore, the passphrase must be entered twice.
+func (c *globalOptions) keystorePassphrase(isNew bool) (string, error) {
+	if passphrase := os.Getenv(_EnvKeystorePassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+	if !prompt.IsInteractive() {
+		return "", goerrors.New("keystore passphrase must be set using the " + _EnvKeystorePassphrase +
+			" environment variable when not running in a terminal")
+	}
+	p := prompt.NewPrompter(os.Stdin, os.Stderr)
+	passphrase, err := p.Secret("Keystore passphrase")
+	if err != nil {
+		return "", err
+	}
+	if isNew {
+		confirm, err := p.Secret("Confirm keystore passphrase")
+		if err != nil {
+			return "", err
+		}
+		if confirm != passphrase {
+			return "", goerrors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
@@ -149,7 +149,7 @@ func (s *State) Uptime() time.Duration {
 // initLogger is responsible for initializing and returning the application logger.
 //
 // The logger created prints any messages below a LevelWarn level to stdout and any messages at or above LevelWarn
-// to stderr.
+// to stderr. When the output format is meant for scripts rather than people, all messages are printed to stderr.
 func (s *State) initLogger(level zerolog.Level) {
 	isDebugEnabled := false
 	if s.productInfo.IsDeveloperBuild || level <= zerolog.DebugLevel {
@@ -160,15 +160,16 @@ func (s *State) initLogger(level zerolog.Level) {
 		Out:        os.Stdout,
 		TimeFormat: "03:04:05PM",
 	}
+	logToStderr := s.config.globalOptions.Output != "" && s.config.globalOptions.Output != OutputFormatTable
 	stdoutCondition := NewFilteredLevelWriterCondition(func(level zerolog.Level) bool {
-		return level < zerolog.WarnLevel
+		return !logToStderr && level < zerolog.WarnLevel
 	})
 	stderrWriter := zerolog.ConsoleWriter{
 		Out:        os.Stderr,
 		TimeFormat: "03:04:05PM",
 	}
 	stderrCondition := NewFilteredLevelWriterCondition(func(level zerolog.Level) bool {
-		return level >= zerolog.WarnLevel
+		return logToStderr || level >= zerolog.WarnLevel
 	})
 	multiWriter := zerolog.MultiLevelWriter(
 		NewFilteredLevelWriter(stdoutWriter, []*FilteredLevelWriterCondition{stdoutCondition}),
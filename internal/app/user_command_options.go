@@ -0,0 +1,190 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+)
+
+// userCommandOptions holds options for the 'user' subcommand and its subcommands.
+type userCommandOptions struct {
+	// unexported variables
+	appState                         *State
+	parent                           *commandOptions
+	configKey                        string
+	isLoaded                         bool
+	userAddRoleCommandOptions        *userAddRoleCommandOptions
+	userAddRoleCommandOptionsOnce    *sync.Once
+	userCreateCommandOptions         *userCreateCommandOptions
+	userCreateCommandOptionsOnce     *sync.Once
+	userDeleteCommandOptions         *userDeleteCommandOptions
+	userDeleteCommandOptionsOnce     *sync.Once
+	userListCommandOptions           *userListCommandOptions
+	userListCommandOptionsOnce       *sync.Once
+	userRemoveRoleCommandOptions     *userRemoveRoleCommandOptions
+	userRemoveRoleCommandOptionsOnce *sync.Once
+}
+
+// jsonUserCommandOptions is just an alias for userCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonUserCommandOptions userCommandOptions
+
+// newUserCommandOptions returns a new object with defaults set.
+func newUserCommandOptions(state *State, parent *commandOptions) *userCommandOptions {
+	configKey := _ConfigCommandUserKey
+
+	return &userCommandOptions{
+		appState:                         state,
+		parent:                           parent,
+		configKey:                        configKey,
+		userAddRoleCommandOptionsOnce:    &sync.Once{},
+		userCreateCommandOptionsOnce:     &sync.Once{},
+		userDeleteCommandOptionsOnce:     &sync.Once{},
+		userListCommandOptionsOnce:       &sync.Once{},
+		userRemoveRoleCommandOptionsOnce: &sync.Once{},
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *userCommandOptions) BindFlags(cmd *cobra.Command) {
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *userCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *userCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *userCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *userCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'user' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *userCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonUserCommandOptions(*c)
+	//lint:ignore SA9005 this function may change in the future to export fields
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *userCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *userCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// AddRole returns the options for the "user add-role" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *userCommandOptions) AddRole() *userAddRoleCommandOptions {
+	c.userAddRoleCommandOptionsOnce.Do(func() {
+		c.userAddRoleCommandOptions = newUserAddRoleCommandOptions(c.appState, c)
+	})
+	return c.userAddRoleCommandOptions
+}
+
+// Create returns the options for the "user create" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *userCommandOptions) Create() *userCreateCommandOptions {
+	c.userCreateCommandOptionsOnce.Do(func() {
+		c.userCreateCommandOptions = newUserCreateCommandOptions(c.appState, c)
+	})
+	return c.userCreateCommandOptions
+}
+
+// Delete returns the options for the "user delete" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *userCommandOptions) Delete() *userDeleteCommandOptions {
+	c.userDeleteCommandOptionsOnce.Do(func() {
+		c.userDeleteCommandOptions = newUserDeleteCommandOptions(c.appState, c)
+	})
+	return c.userDeleteCommandOptions
+}
+
+// List returns the options for the "user list" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *userCommandOptions) List() *userListCommandOptions {
+	c.userListCommandOptionsOnce.Do(func() {
+		c.userListCommandOptions = newUserListCommandOptions(c.appState, c)
+	})
+	return c.userListCommandOptions
+}
+
+// RemoveRole returns the options for the "user remove-role" subcommand.
+//
+// If the options object has not been initialized, it is automatically initialized. However, the settings
+// are *not* automatically loaded when the object is initialized. To determine if the settings have been loaded, use
+// the object's IsLoaded() function.
+func (c *userCommandOptions) RemoveRole() *userRemoveRoleCommandOptions {
+	c.userRemoveRoleCommandOptionsOnce.Do(func() {
+		c.userRemoveRoleCommandOptions = newUserRemoveRoleCommandOptions(c.appState, c)
+	})
+	return c.userRemoveRoleCommandOptions
+}
+
+// viperUserCommandOptions holds the options for any 'user' subcommands.
+type viperUserCommandOptions struct {
+	AddRole    viperUserAddRoleCommandOptions    `mapstructure:"add_role"`
+	Create     viperUserCreateCommandOptions     `mapstructure:"create"`
+	Delete     viperUserDeleteCommandOptions     `mapstructure:"delete"`
+	List       viperUserListCommandOptions       `mapstructure:"list"`
+	RemoveRole viperUserRemoveRoleCommandOptions `mapstructure:"remove_role"`
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// userCreateCommandOptions holds options for the 'user create' subcommand.
+type userCreateCommandOptions struct {
+	AccountID     string `json:"account_id"`
+	AccountName   string `json:"account_name"`
+	EmailAddress  string `json:"email_address"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	ResetPassword bool   `json:"reset_password"`
+	Role          string `json:"role"`
+
+	// unexported variables
+	appState  *State
+	parent    *userCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonUserCreateCommandOptions is just an alias for userCreateCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonUserCreateCommandOptions userCreateCommandOptions
+
+// newUserCreateCommandOptions returns a new object with defaults set.
+func newUserCreateCommandOptions(state *State, parent *userCommandOptions) *userCreateCommandOptions {
+	configKey := _ConfigCommandUserCreateKey
+	viper.SetDefault(fmt.Sprintf("%s.account_id", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.email_address", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.first_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.last_name", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.reset_password", configKey), false)
+	viper.SetDefault(fmt.Sprintf("%s.role", configKey), _DefaultUserRole)
+
+	return &userCreateCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *userCreateCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-id
+	flags.String("account-id", "", "ID of the account to create the user in")
+	viper.BindPFlag(fmt.Sprintf("%s.account_id", c.configKey), flags.Lookup("account-id"))
+	viper.BindEnv(fmt.Sprintf("%s.account_id", c.configKey), fmt.Sprintf("%sACCOUNT_ID", envPrefix))
+
+	// --account-name
+	flags.String("account-name", "", "name of the account to create the user in")
+	viper.BindPFlag(fmt.Sprintf("%s.account_name", c.configKey), flags.Lookup("account-name"))
+	viper.BindEnv(fmt.Sprintf("%s.account_name", c.configKey), fmt.Sprintf("%sACCOUNT_NAME", envPrefix))
+
+	// --email-address
+	flags.String("email-address", "", "e-mail address of the user")
+	viper.BindPFlag(fmt.Sprintf("%s.email_address", c.configKey), flags.Lookup("email-address"))
+	viper.BindEnv(fmt.Sprintf("%s.email_address", c.configKey), fmt.Sprintf("%sEMAIL_ADDRESS", envPrefix))
+
+	// --first-name
+	flags.String("first-name", "", "first name of the user")
+	viper.BindPFlag(fmt.Sprintf("%s.first_name", c.configKey), flags.Lookup("first-name"))
+	viper.BindEnv(fmt.Sprintf("%s.first_name", c.configKey), fmt.Sprintf("%sFIRST_NAME", envPrefix))
+
+	// --last-name
+	flags.String("last-name", "", "last name of the user")
+	viper.BindPFlag(fmt.Sprintf("%s.last_name", c.configKey), flags.Lookup("last-name"))
+	viper.BindEnv(fmt.Sprintf("%s.last_name", c.configKey), fmt.Sprintf("%sLAST_NAME", envPrefix))
+
+	// --reset-password
+	flags.Bool("reset-password", false, "send the user a password reset email")
+	viper.BindPFlag(fmt.Sprintf("%s.reset_password", c.configKey), flags.Lookup("reset-password"))
+	viper.BindEnv(fmt.Sprintf("%s.reset_password", c.configKey), fmt.Sprintf("%sRESET_PASSWORD", envPrefix))
+
+	// --role
+	flags.String("role", _DefaultUserRole, "name or ID of the role given to the user in the account")
+	viper.BindPFlag(fmt.Sprintf("%s.role", c.configKey), flags.Lookup("role"))
+	viper.BindEnv(fmt.Sprintf("%s.role", c.configKey), fmt.Sprintf("%sROLE", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *userCreateCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *userCreateCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *userCreateCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.User.Create
+	logger := c.appState.logger
+
+	// an account must be given
+	if viperConfig.AccountID == "" && viperConfig.AccountName == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "account_id",
+			viperConfig.AccountID, goerrors.New("an account ID or account name is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "account_id").
+			Str("value", viperConfig.AccountID).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// the user must have an e-mail address
+	if viperConfig.EmailAddress == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "email_address",
+			viperConfig.EmailAddress, goerrors.New("an e-mail address is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "email_address").
+			Str("value", viperConfig.EmailAddress).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// the user must have a name
+	if viperConfig.FirstName == "" || viperConfig.LastName == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "first_name",
+			viperConfig.FirstName, goerrors.New("a first and last name are required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "first_name").
+			Str("value", viperConfig.FirstName).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// the user must be given a role
+	if viperConfig.Role == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "role",
+			viperConfig.Role, goerrors.New("a role is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "role").
+			Str("value", viperConfig.Role).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// save options
+	c.AccountID = viperConfig.AccountID
+	c.AccountName = viperConfig.AccountName
+	c.EmailAddress = viperConfig.EmailAddress
+	c.FirstName = viperConfig.FirstName
+	c.LastName = viperConfig.LastName
+	c.ResetPassword = viperConfig.ResetPassword
+	c.Role = viperConfig.Role
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *userCreateCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'user create' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *userCreateCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonUserCreateCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *userCreateCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *userCreateCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperUserCreateCommandOptions holds the options for the 'user create' subcommand.
+type viperUserCreateCommandOptions struct {
+	AccountID     string `mapstructure:"account_id"`
+	AccountName   string `mapstructure:"account_name"`
+	EmailAddress  string `mapstructure:"email_address"`
+	FirstName     string `mapstructure:"first_name"`
+	LastName      string `mapstructure:"last_name"`
+	ResetPassword bool   `mapstructure:"reset_password"`
+	Role          string `mapstructure:"role"`
+}
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+)
+
+// userDeleteCommandOptions holds options for the 'user delete' subcommand.
+type userDeleteCommandOptions struct {
+	AssumeYes bool `json:"assume_yes"`
+
+	// unexported variables
+	appState  *State
+	parent    *userCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonUserDeleteCommandOptions is just an alias for userDeleteCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonUserDeleteCommandOptions userDeleteCommandOptions
+
+// newUserDeleteCommandOptions returns a new object with defaults set.
+func newUserDeleteCommandOptions(state *State, parent *userCommandOptions) *userDeleteCommandOptions {
+	configKey := _ConfigCommandUserDeleteKey
+	viper.SetDefault(fmt.Sprintf("%s.assume_yes", configKey), false)
+
+	return &userDeleteCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *userDeleteCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --yes
+	flags.BoolP("yes", "y", false, "delete the users without asking for confirmation")
+	viper.BindPFlag(fmt.Sprintf("%s.assume_yes", c.configKey), flags.Lookup("yes"))
+	viper.BindEnv(fmt.Sprintf("%s.assume_yes", c.configKey), fmt.Sprintf("%sASSUME_YES", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *userDeleteCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *userDeleteCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *userDeleteCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.User.Delete
+
+	// save options
+	c.AssumeYes = viperConfig.AssumeYes
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *userDeleteCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'user delete' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *userDeleteCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonUserDeleteCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *userDeleteCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *userDeleteCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperUserDeleteCommandOptions holds the options for the 'user delete' subcommand.
+type viperUserDeleteCommandOptions struct {
+	AssumeYes bool `mapstructure:"assume_yes"`
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+)
+
+// userListCommandOptions holds options for the 'user list' subcommand.
+type userListCommandOptions struct {
+	AccountID string `json:"account_id"`
+	Email     string `json:"email"`
+
+	// unexported variables
+	appState  *State
+	parent    *userCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonUserListCommandOptions is just an alias for userListCommandOptions that is used during marshalling and
+// unmarshalling to prevent infinite recursion.
+type jsonUserListCommandOptions userListCommandOptions
+
+// newUserListCommandOptions returns a new object with defaults set.
+func newUserListCommandOptions(state *State, parent *userCommandOptions) *userListCommandOptions {
+	configKey := _ConfigCommandUserListKey
+	viper.SetDefault(fmt.Sprintf("%s.account_id", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.email", configKey), "")
+
+	return &userListCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *userListCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-id
+	flags.String("account-id", "", "only list users with a role in the account with this ID")
+	viper.BindPFlag(fmt.Sprintf("%s.account_id", c.configKey), flags.Lookup("account-id"))
+	viper.BindEnv(fmt.Sprintf("%s.account_id", c.configKey), fmt.Sprintf("%sACCOUNT_ID", envPrefix))
+
+	// --email
+	flags.String("email", "", "only list users whose e-mail address contains this text")
+	viper.BindPFlag(fmt.Sprintf("%s.email", c.configKey), flags.Lookup("email"))
+	viper.BindEnv(fmt.Sprintf("%s.email", c.configKey), fmt.Sprintf("%sEMAIL", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *userListCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *userListCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *userListCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.User.List
+
+	// save options
+	c.AccountID = viperConfig.AccountID
+	c.Email = viperConfig.Email
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *userListCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'user list' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *userListCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonUserListCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *userListCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *userListCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperUserListCommandOptions holds the options for the 'user list' subcommand.
+type viperUserListCommandOptions struct {
+	AccountID string `mapstructure:"account_id"`
+	Email     string `mapstructure:"email"`
+}
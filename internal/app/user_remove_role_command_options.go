@@ -0,0 +1,150 @@
+package app
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/build"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// userRemoveRoleCommandOptions holds options for the 'user remove-role' subcommand.
+type userRemoveRoleCommandOptions struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+
+	// unexported variables
+	appState  *State
+	parent    *userCommandOptions
+	configKey string
+	isLoaded  bool
+}
+
+// jsonUserRemoveRoleCommandOptions is just an alias for userRemoveRoleCommandOptions that is used during marshalling
+// and unmarshalling to prevent infinite recursion.
+type jsonUserRemoveRoleCommandOptions userRemoveRoleCommandOptions
+
+// newUserRemoveRoleCommandOptions returns a new object with defaults set.
+func newUserRemoveRoleCommandOptions(state *State, parent *userCommandOptions) *userRemoveRoleCommandOptions {
+	configKey := _ConfigCommandUserRemoveRoleKey
+	viper.SetDefault(fmt.Sprintf("%s.account_id", configKey), "")
+	viper.SetDefault(fmt.Sprintf("%s.account_name", configKey), "")
+
+	return &userRemoveRoleCommandOptions{
+		appState:  state,
+		parent:    parent,
+		configKey: configKey,
+	}
+}
+
+// BindFlags is used to add command-line flags and bind them to viper configuration keys.
+func (c *userRemoveRoleCommandOptions) BindFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	envPrefix := fmt.Sprintf("%s%s_", build.AppEnvPrefix, strings.ReplaceAll(strings.ToUpper(c.configKey), ".", "_"))
+
+	// --account-id
+	flags.String("account-id", "", "ID of the account to remove the user from")
+	viper.BindPFlag(fmt.Sprintf("%s.account_id", c.configKey), flags.Lookup("account-id"))
+	viper.BindEnv(fmt.Sprintf("%s.account_id", c.configKey), fmt.Sprintf("%sACCOUNT_ID", envPrefix))
+
+	// --account-name
+	flags.String("account-name", "", "name of the account to remove the user from")
+	viper.BindPFlag(fmt.Sprintf("%s.account_name", c.configKey), flags.Lookup("account-name"))
+	viper.BindEnv(fmt.Sprintf("%s.account_name", c.configKey), fmt.Sprintf("%sACCOUNT_NAME", envPrefix))
+}
+
+// ConfigKey returns the base name of the viper configuration key where the options are stored.
+func (c *userRemoveRoleCommandOptions) ConfigKey() string {
+	return c.configKey
+}
+
+// IsLoaded returns whether or not the configuration settings have been loaded.
+func (c *userRemoveRoleCommandOptions) IsLoaded() bool {
+	return c.isLoaded
+}
+
+// Load converts the corresponding viper configuration and loads it into this configuration object, validating
+// settings along the way.
+//
+// If the options have already been loaded, they will not be loaded again.
+//
+// The following errors are returned by this function:
+// ConfigValidateFailure
+func (c *userRemoveRoleCommandOptions) Load() errorx.Error {
+	if c.isLoaded {
+		return nil
+	}
+	if errx := c.parent.Load(); errx != nil {
+		return errx
+	}
+	viperConfig := c.appState.config.viperConfig.CommandOptions.User.RemoveRole
+	logger := c.appState.logger
+
+	// an account must be given
+	if viperConfig.AccountID == "" && viperConfig.AccountName == "" {
+		errx := errors.NewConfigValidateFailure(c.appState.config.globalOptions.ConfigFile, "account_id",
+			viperConfig.AccountID, goerrors.New("an account ID or account name is required"))
+		logger.Error().
+			Err(errx).
+			Str("option", "account_id").
+			Str("value", viperConfig.AccountID).
+			Msg(errx.Error())
+		return errx
+	}
+
+	// save options
+	c.AccountID = viperConfig.AccountID
+	c.AccountName = viperConfig.AccountName
+
+	c.isLoaded = true
+	return nil
+}
+
+// LogSettings simply writes the object settings to the log.
+func (c *userRemoveRoleCommandOptions) LogSettings(recurse bool) {
+	if recurse {
+		c.parent.LogSettings(recurse)
+	}
+	c.appState.logger.Debug().Any("options", c.StringMap()).Msg("loaded 'user remove-role' subcommand options")
+}
+
+// MarshalJSON overrides how the object is marshalled to JSON to alter how field values are presented or to
+// add additional fields.
+//
+// Any errors returned by this function are a result of calling json.Marshal().
+func (c *userRemoveRoleCommandOptions) MarshalJSON() ([]byte, error) {
+	cfg := jsonUserRemoveRoleCommandOptions(*c)
+	return json.Marshal(&cfg)
+}
+
+// StringMap returns a map of strings to any type as a representation of the configuration.
+func (c *userRemoveRoleCommandOptions) StringMap() map[string]any {
+	asString := c.String()
+	var stringMap map[string]any
+	if err := json.Unmarshal([]byte(asString), &stringMap); err != nil {
+		return map[string]any{
+			"error": fmt.Sprintf("error marshalling object to JSON: %s", err.Error()),
+		}
+	}
+	return stringMap
+}
+
+// String returns a string representation of the configuration as JSON.
+func (c *userRemoveRoleCommandOptions) String() string {
+	output, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("error marshalling object to JSON: %s", err.Error())
+	}
+	return string(output)
+}
+
+// viperUserRemoveRoleCommandOptions holds the options for the 'user remove-role' subcommand.
+type viperUserRemoveRoleCommandOptions struct {
+	AccountID   string `mapstructure:"account_id"`
+	AccountName string `mapstructure:"account_name"`
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccountTypes holds the account types supported by the S1 API.
+var AccountTypes = []string{"Trial", "Paid"}
+
+// ParseAccountType returns the account type matching the given value without regard to case.
+func ParseAccountType(value string) (string, error) {
+	for _, t := range AccountTypes {
+		if strings.EqualFold(value, t) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("account type must be one of: %s", strings.Join(AccountTypes, ", "))
+}
+
+// ValidateAccountType ensures the account type is one supported by the S1 API.
+func ValidateAccountType(value string) error {
+	if t, err := ParseAccountType(value); err != nil || t != value {
+		return fmt.Errorf("account type must be one of: %s", strings.Join(AccountTypes, ", "))
+	}
+	return nil
+}
+
+// ValidateProvisionConcurrency ensures the number of accounts provisioned at the same time is within the allowed
+// range.
+func ValidateProvisionConcurrency(value int) error {
+	if value < 1 || value > MaxProvisionConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", MaxProvisionConcurrency)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/commands/deprovision"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// NewCommand creates a new Command object.
+func NewCommand(state *app.State) *Command {
+	cmd := &Command{
+		appState: state,
+	}
+	cmd.Use = "account"
+	cmd.Short = "Manages existing accounts."
+	cmd.Long = `This command is used to look at and manage accounts which already exist on the SentinelOne platform.
+
+To create new accounts, use the 'provision account' command.`
+
+	// add flags
+	state.Config().CommandOptions().Account().BindFlags(&cmd.Command)
+
+	// add commands
+	cmd.AddCommand(&deprovision.NewAccountExpireCommand(state).Command)
+	cmd.AddCommand(&newExtendCommand(state).Command)
+	cmd.AddCommand(&newListCommand(state).Command)
+
+	return cmd
+}
@@ -0,0 +1,324 @@
+package account
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/csvfile"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// Actions reported for each account.
+const (
+	actionExtended    = "extended"
+	actionFailed      = "failed"
+	actionWouldExtend = "would extend"
+)
+
+// extendCommand is the object for executing the 'account extend' command.
+type extendCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+	s1Client *api.S1Client
+}
+
+// extendRecord holds a single account to extend.
+//
+// Exactly one of the account ID, account name or external ID is expected to be set. The line is the line of the CSV
+// file the account was read from or 0 if the account was given on the command-line.
+type extendRecord struct {
+	AccountID   string `csv:"account_id"`
+	AccountName string `csv:"account_name"`
+	ExternalID  string `csv:"external_id"`
+	Expires     string `csv:"expires"`
+
+	// unexported variables
+	line int
+}
+
+// extendResult holds the result of extending a single account.
+type extendResult struct {
+	Line          int    `csv:"line" json:"line" yaml:"line"`
+	AccountID     string `csv:"account_id" json:"account_id" yaml:"account_id"`
+	AccountName   string `csv:"account_name" json:"account_name" yaml:"account_name"`
+	ExternalID    string `csv:"external_id" json:"external_id" yaml:"external_id"`
+	OldExpiration string `csv:"old_expiration" json:"old_expiration" yaml:"old_expiration"`
+	NewExpiration string `csv:"new_expiration" json:"new_expiration" yaml:"new_expiration"`
+	Action        string `csv:"action" json:"action" yaml:"action"`
+	Error         string `csv:"error" json:"error,omitempty" yaml:"error,omitempty"`
+
+	// unexported variables
+	account       *api.S1Account
+	newExpiration time.Time
+	errx          errorx.Error
+}
+
+// newExtendCommand creates a new extendCommand object.
+func newExtendCommand(state *app.State) *extendCommand {
+	cmd := &extendCommand{
+		appState: state,
+	}
+	cmd.Use = "extend"
+	cmd.Short = "Extends the expiration of active accounts."
+	cmd.Long = `This command is used to change when active accounts on the SentinelOne platform expire.
+
+Accounts may be given by ID, name or external ID using comma-separated lists or read from a CSV file with the
+columns account_id, account_name, external_id and expires. Each row of the CSV must set one of the first three
+columns. The expires column is optional and overrides --expires for that row. Every account with a matching external
+ID is extended.
+
+The new expiration may be a duration from now (eg: 72h) or an RFC3339 date and time and must be in the future. Only
+active accounts can be extended. Expired accounts must be reactivated using the 'provision account' command with
+--reactivate-expired-account, and accounts which never expire are left alone.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().Account().Extend().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *extendCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Account().Extend()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	logger := c.appState.Logger()
+
+	// gather the accounts given on the command-line and in the CSV
+	records := []extendRecord{}
+	for _, id := range csvfile.SplitList(cmdOpts.AccountIDs) {
+		records = append(records, extendRecord{AccountID: id})
+	}
+	for _, name := range csvfile.SplitList(cmdOpts.AccountNames) {
+		records = append(records, extendRecord{AccountName: name})
+	}
+	for _, externalID := range csvfile.SplitList(cmdOpts.ExternalIDs) {
+		records = append(records, extendRecord{ExternalID: externalID})
+	}
+	if cmdOpts.CSVSource != "" {
+		csvRecords, errx := csvfile.Read[extendRecord](c.appState, cmdOpts.CSVSource, cmdOpts.CSVSeparator)
+		if errx != nil {
+			return errx
+		}
+		for _, csvRecord := range csvRecords {
+			record := csvRecord.Value
+			record.line = csvRecord.Line
+			records = append(records, record)
+		}
+	}
+
+	builder, errx := api.NewS1ClientBuilderFromConfig(c.appState)
+	if errx != nil {
+		return errx
+	}
+	c.s1Client = builder.Build()
+
+	// look up every account first so that nothing is changed if the user decides not to go ahead
+	results := []extendResult{}
+	seen := map[string]bool{}
+	pending := 0
+	for _, record := range records {
+		for _, result := range c.planRecord(record, cmdOpts.Expires) {
+			if result.account != nil {
+				if seen[result.account.ID] {
+					continue
+				}
+				seen[result.account.ID] = true
+			}
+			if result.errx == nil {
+				pending++
+			}
+			results = append(results, result)
+		}
+	}
+
+	// confirm the changes
+	if pending > 0 && !cmdOpts.DryRun && !cmdOpts.AssumeYes {
+		if !prompt.IsInteractive() {
+			errx := errors.NewUsageError(goerrors.New("use --yes to extend accounts when not running in a terminal"))
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		p := prompt.NewPrompter(os.Stdin, os.Stdout)
+		proceed, err := p.Confirm(fmt.Sprintf("Extend the expiration of %d account(s)?", pending), false)
+		if err != nil {
+			errx := errors.NewGeneralFailure("failed to read answer", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		if !proceed {
+			logger.Info().Msg("no accounts were extended")
+			return nil
+		}
+	}
+
+	// extend the accounts
+	var firstErr errorx.Error
+	failures := 0
+	for i := range results {
+		result := &results[i]
+		if result.errx == nil {
+			if cmdOpts.DryRun {
+				result.Action = actionWouldExtend
+			} else {
+				c.extendAccount(result)
+			}
+		}
+		if result.errx != nil {
+			if firstErr == nil {
+				firstErr = result.errx
+			}
+			failures++
+		}
+	}
+	if errx := c.appState.Output().Write(results); errx != nil {
+		return errx
+	}
+	if firstErr != nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("%d of %d accounts could not be extended", failures,
+			len(results)), firstErr)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	if !cmdOpts.DryRun {
+		logger.Info().Msg("all accounts have been extended")
+	}
+	return nil
+}
+
+// extendAccount updates the expiration of the account in the given result.
+func (c *extendCommand) extendAccount(result *extendResult) {
+	logger := c.appState.Logger().With().Int("line", result.Line).Str("account_id", result.AccountID).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+
+	account, errx := s1Client.UpdateAccountExpiration(result.AccountID, result.newExpiration)
+	if errx != nil {
+		result.setFailed(errx)
+		logger.Error().Err(errx).Msg("account could not be extended")
+		return
+	}
+	result.Action = actionExtended
+	if !account.Expiration.IsZero() {
+		result.NewExpiration = account.Expiration.Format(time.RFC3339)
+	}
+	logger.Info().Str("expiration", result.NewExpiration).Msg("account has been extended")
+}
+
+// planRecord finds the accounts for the given record and works out their new expiration.
+//
+// A result is returned for every account found. If no accounts are found or the record cannot be processed, a single
+// failed result is returned instead.
+func (c *extendCommand) planRecord(record extendRecord, defaultExpires string) []extendResult {
+	logger := c.appState.Logger().With().Int("line", record.line).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+	failed := func(errx errorx.Error) []extendResult {
+		result := extendResult{
+			Line:        record.line,
+			AccountID:   record.AccountID,
+			AccountName: record.AccountName,
+			ExternalID:  record.ExternalID,
+		}
+		result.setFailed(errx)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return []extendResult{result}
+	}
+
+	// work out the new expiration
+	expires := record.Expires
+	if expires == "" {
+		expires = defaultExpires
+	}
+	if expires == "" {
+		return failed(errors.NewUsageError(goerrors.New("no expiration was given for the account")))
+	}
+	newExpiration, err := api.ParseExpiration(expires)
+	if err != nil {
+		return failed(errors.NewUsageError(fmt.Errorf("expiration '%s' must be an RFC3339 date and time or a "+
+			"duration: %w", expires, err)))
+	}
+	if !newExpiration.After(time.Now()) {
+		return failed(errors.NewUsageError(fmt.Errorf("expiration '%s' is not in the future", expires)))
+	}
+
+	// find the accounts
+	var accounts []*api.S1Account
+	var errx errorx.Error
+	switch {
+	case record.AccountID != "":
+		var account *api.S1Account
+		if account, errx = s1Client.FindAccountByID(record.AccountID); account != nil {
+			accounts = append(accounts, account)
+		}
+	case record.AccountName != "":
+		var account *api.S1Account
+		if account, errx = s1Client.FindAccount(record.AccountName); account != nil {
+			accounts = append(accounts, account)
+		}
+	case record.ExternalID != "":
+		accounts, errx = s1Client.FindAccountsByExternalID(record.ExternalID)
+	default:
+		return failed(errors.NewUsageError(goerrors.New("an account ID, account name or external ID is required")))
+	}
+	if errx != nil {
+		return failed(errx)
+	}
+	if len(accounts) == 0 {
+		return failed(errors.NewGeneralFailure("account was not found", goerrors.New("no matching accounts")))
+	}
+
+	// only active accounts which already expire can be extended
+	results := make([]extendResult, 0, len(accounts))
+	for _, account := range accounts {
+		result := extendResult{
+			Line:          record.line,
+			AccountID:     account.ID,
+			AccountName:   account.Name,
+			ExternalID:    account.ExternalID,
+			NewExpiration: newExpiration.UTC().Format(time.RFC3339),
+			account:       account,
+			newExpiration: newExpiration,
+		}
+		if !account.Expiration.IsZero() {
+			result.OldExpiration = account.Expiration.Format(time.RFC3339)
+		}
+		switch {
+		case account.State == "expired":
+			result.setFailed(errors.NewGeneralFailure("account cannot be extended",
+				goerrors.New("account is expired and must be reactivated instead")))
+		case account.State != "active":
+			result.setFailed(errors.NewGeneralFailure("account cannot be extended",
+				fmt.Errorf("account is %s", account.State)))
+		case account.Expiration.IsZero():
+			result.setFailed(errors.NewGeneralFailure("account cannot be extended",
+				goerrors.New("account never expires")))
+		}
+		if result.errx != nil {
+			logger.Error().Err(result.errx).Str("account_id", account.ID).Msg(result.errx.Error())
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// setFailed marks the result as failed with the given error.
+func (r *extendResult) setFailed(errx errorx.Error) {
+	r.Action = actionFailed
+	r.Error = errx.Error()
+	r.errx = errx
+}
@@ -0,0 +1,128 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// listCommand is the object for executing the 'account list' command.
+type listCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// accountRow holds the details of a single account in the output.
+type accountRow struct {
+	ID          string `csv:"id" json:"id" yaml:"id"`
+	Name        string `csv:"name" json:"name" yaml:"name"`
+	AccountType string `csv:"account_type" json:"account_type" yaml:"account_type"`
+	State       string `csv:"state" json:"state" yaml:"state"`
+	Expiration  string `csv:"expiration" json:"expiration" yaml:"expiration"`
+	ExternalID  string `csv:"external_id" json:"external_id" yaml:"external_id"`
+	BillingMode string `csv:"billing_mode" json:"billing_mode" yaml:"billing_mode"`
+}
+
+// newListCommand creates a new listCommand object.
+func newListCommand(state *app.State) *listCommand {
+	cmd := &listCommand{
+		appState: state,
+	}
+	cmd.Use = "list"
+	cmd.Short = "Lists accounts."
+	cmd.Long = `This command is used to list the accounts on the SentinelOne platform.
+
+All accounts are listed unless filters are given. When more than one filter is given, only accounts matching all of
+them are listed. Accounts which never expire are never listed when filtering on --expiring-before.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().Account().List().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *listCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Account().List()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	logger := c.appState.Logger()
+
+	var expiringBefore time.Time
+	if cmdOpts.ExpiringBefore != "" {
+		var err error
+		if expiringBefore, err = api.ParseExpiration(cmdOpts.ExpiringBefore); err != nil {
+			errx := errors.NewUsageError(fmt.Errorf("--expiring-before must be an RFC3339 date and time or a "+
+				"duration: %w", err))
+			logger.Error().Err(errx).Str("expiring_before", cmdOpts.ExpiringBefore).Msg(errx.Error())
+			return errx
+		}
+	}
+
+	builder, errx := api.NewS1ClientBuilderFromConfig(c.appState)
+	if errx != nil {
+		return errx
+	}
+	s1Client := builder.Build()
+
+	// the API filters on the name, state and type while the remaining filters are applied as accounts are read
+	filters := map[string]string{}
+	if cmdOpts.Name != "" {
+		filters["name__contains"] = cmdOpts.Name
+	}
+	if cmdOpts.States != "" {
+		filters["states"] = cmdOpts.States
+	}
+	if cmdOpts.AccountType != "" {
+		filters["accountType"] = cmdOpts.AccountType
+	}
+	rows := []accountRow{}
+	iter := s1Client.ListAccounts(&api.S1ListOptions{
+		Filters: filters,
+	})
+	for iter.Next() {
+		account := iter.Item()
+		if cmdOpts.ExternalID != "" && account.ExternalID != cmdOpts.ExternalID {
+			continue
+		}
+		if !expiringBefore.IsZero() && (account.Expiration.IsZero() || !account.Expiration.Before(expiringBefore)) {
+			continue
+		}
+		rows = append(rows, newAccountRow(account))
+	}
+	if errx := iter.Err(); errx != nil {
+		return errx
+	}
+	logger.Debug().Int("accounts", len(rows)).Msg("listed accounts")
+
+	return c.appState.Output().Write(rows)
+}
+
+// newAccountRow returns the output row for the given account.
+func newAccountRow(account *api.S1Account) accountRow {
+	row := accountRow{
+		ID:          account.ID,
+		Name:        account.Name,
+		AccountType: account.AccountType,
+		State:       account.State,
+		ExternalID:  account.ExternalID,
+		BillingMode: account.BillingMode,
+	}
+	if !account.Expiration.IsZero() {
+		row.Expiration = account.Expiration.Format(time.RFC3339)
+	}
+	return row
+}
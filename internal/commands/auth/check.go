@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// checkCommand is the object for executing the 'auth check' command.
+type checkCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// tokenInfo holds the details of the API token written when the output is meant for scripts.
+type tokenInfo struct {
+	TenantURL   string `csv:"tenant_url" json:"tenant_url" yaml:"tenant_url"`
+	UserID      string `csv:"user_id" json:"user_id" yaml:"user_id"`
+	UserEmail   string `csv:"user_email" json:"user_email" yaml:"user_email"`
+	Scope       string `csv:"scope" json:"scope" yaml:"scope"`
+	Roles       string `csv:"roles" json:"roles" yaml:"roles"`
+	CreatedAt   string `csv:"created_at" json:"created_at" yaml:"created_at"`
+	ExpiresAt   string `csv:"expires_at" json:"expires_at" yaml:"expires_at"`
+	ExpiresSoon bool   `csv:"expires_soon" json:"expires_soon" yaml:"expires_soon"`
+}
+
+// newCheckCommand creates a new checkCommand object.
+func newCheckCommand(state *app.State) *checkCommand {
+	cmd := &checkCommand{
+		appState: state,
+	}
+	cmd.Use = "check"
+	cmd.Short = "Checks the API token."
+	cmd.Long = `This command is used to check that the API token is valid and to show when it expires.
+
+The user who owns the token, the scope of the user and when the token was created and expires are shown. A warning is
+shown if the token expires within the time set by --token-expiry-warning. The command fails if the token has expired.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *checkCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Auth()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+	globalOpts := c.appState.Config().GlobalOptions()
+
+	// find the owner of the token first as this fails with a clearer error if the token is not valid at all
+	user, errx := s1Client.GetCurrentUser()
+	if errx != nil {
+		return errx
+	}
+	details, errx := s1Client.CheckAPIToken(globalOpts.TokenExpiryWarning)
+	if errx != nil {
+		return errx
+	}
+
+	roles := make([]string, 0, len(user.ScopeRoles))
+	for _, role := range user.ScopeRoles {
+		roles = append(roles, role.RoleName)
+	}
+	if output := c.appState.Output(); !output.IsTable() {
+		return output.Write(tokenInfo{
+			TenantURL:   globalOpts.TenantURL,
+			UserID:      user.ID,
+			UserEmail:   user.EmailAddress,
+			Scope:       user.Scope,
+			Roles:       strings.Join(roles, ","),
+			CreatedAt:   details.CreatedAt.Format(time.RFC3339),
+			ExpiresAt:   details.ExpiresAt.Format(time.RFC3339),
+			ExpiresSoon: details.ExpiresWithin(globalOpts.TokenExpiryWarning),
+		})
+	}
+	fmt.Printf("Tenant URL: %s\n", globalOpts.TenantURL)
+	fmt.Printf("User:       %s (%s)\n", user.EmailAddress, user.ID)
+	fmt.Printf("Scope:      %s\n", user.Scope)
+	if len(roles) > 0 {
+		fmt.Printf("Roles:      %s\n", strings.Join(roles, ", "))
+	}
+	fmt.Printf("Created:    %s\n", details.CreatedAt.Local().Format(time.RFC1123))
+	fmt.Printf("Expires:    %s (in %s)\n", details.ExpiresAt.Local().Format(time.RFC1123), formatRemaining(
+		time.Until(details.ExpiresAt)))
+	if details.ExpiresWithin(globalOpts.TokenExpiryWarning) {
+		fmt.Printf("\nWARNING: the API token expires soon and should be rotated.\n")
+	}
+	return nil
+}
+
+// formatRemaining returns the time left before the token expires in days and hours.
+func formatRemaining(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int((d % (24 * time.Hour)) / time.Hour)
+	if days == 0 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d days %d hours", days, hours)
+}
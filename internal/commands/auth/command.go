@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// NewCommand creates a new Command object.
+func NewCommand(state *app.State) *Command {
+	cmd := &Command{
+		appState: state,
+	}
+	cmd.Use = "auth"
+	cmd.Short = "Manages the API token."
+	cmd.Long = `This command is used to manage the API token used to access the SentinelOne API.`
+
+	// add flags
+	state.Config().CommandOptions().Auth().BindFlags(&cmd.Command)
+
+	// add commands
+	cmd.AddCommand(&newCheckCommand(state).Command)
+	cmd.AddCommand(&newRotateCommand(state).Command)
+
+	return cmd
+}
+
+// newS1Client returns a client for the S1 API using the loaded configuration.
+func newS1Client(state *app.State) (*api.S1Client, errorx.Error) {
+	builder, errx := api.NewS1ClientBuilderFromConfig(state)
+	if errx != nil {
+		return nil, errx
+	}
+	return builder.Build(), nil
+}
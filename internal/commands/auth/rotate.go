@@ -0,0 +1,156 @@
+package auth
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// rotateCommand is the object for executing the 'auth rotate' command.
+type rotateCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// rotateResult holds the result of rotating the API token written when the output is meant for scripts.
+//
+// The new token itself is never written; it can be found in the location it was saved to.
+type rotateResult struct {
+	UserID    string `csv:"user_id" json:"user_id" yaml:"user_id"`
+	UserEmail string `csv:"user_email" json:"user_email" yaml:"user_email"`
+	Location  string `csv:"location" json:"location" yaml:"location"`
+	ExpiresAt string `csv:"expires_at" json:"expires_at" yaml:"expires_at"`
+}
+
+// newRotateCommand creates a new rotateCommand object.
+func newRotateCommand(state *app.State) *rotateCommand {
+	cmd := &rotateCommand{
+		appState: state,
+	}
+	cmd.Use = "rotate"
+	cmd.Short = "Replaces the API token with a new one."
+	cmd.Long = `This command is used to generate a new API token for the user who owns the current token.
+
+The new token is checked against the SentinelOne API and then saved in place of the current one. If the api_key
+setting refers to the keystore or a file, the token is replaced there. Otherwise the api_key setting is replaced in
+the configuration file, either in the selected profile or in the global settings.
+
+The current token stops working as soon as the new token is generated.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().Auth().Rotate().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *rotateCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Auth().Rotate()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	globalOpts := c.appState.Config().GlobalOptions()
+
+	// make sure the new token can be saved before the current one is replaced
+	if errx := globalOpts.CheckAPIKeyUpdatable(); errx != nil {
+		return errx
+	}
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+	user, errx := s1Client.GetCurrentUser()
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("user_email", user.EmailAddress).Str("user_id", user.ID).Logger()
+
+	// the current token stops working straight away so give the user a chance to back out
+	if !cmdOpts.AssumeYes {
+		if !prompt.IsInteractive() {
+			errx := errors.NewUsageError(goerrors.New("use --yes to rotate the API token when not running in a terminal"))
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		p := prompt.NewPrompter(os.Stdin, os.Stdout)
+		proceed, err := p.Confirm(fmt.Sprintf("Replace the API token for '%s'? The current token will stop working.",
+			user.EmailAddress), false)
+		if err != nil {
+			errx := errors.NewGeneralFailure("failed to read answer", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		if !proceed {
+			logger.Info().Msg("API token was not rotated")
+			return nil
+		}
+	}
+
+	// generate the new token and make sure it works before saving it
+	token, errx := s1Client.GenerateAPIToken()
+	if errx != nil {
+		return errx
+	}
+	logger.Info().Time("expires_at", token.ExpiresAt).Msg("generated new API token")
+	if errx := c.verifyToken(token.Token, user); errx != nil {
+		// the old token no longer works so the new one must not be lost
+		fmt.Fprintf(os.Stderr, "\nThe new API token could not be verified and has NOT been saved. Save it manually:\n"+
+			"%s\n\n", token.Token)
+		return errx
+	}
+	location, errx := globalOpts.UpdateAPIKey(token.Token)
+	if errx != nil {
+		fmt.Fprintf(os.Stderr, "\nThe new API token could not be saved. Save it manually:\n%s\n\n", token.Token)
+		return errx
+	}
+	logger.Info().Str("location", location).Msg("saved new API token")
+
+	if output := c.appState.Output(); !output.IsTable() {
+		return output.Write(rotateResult{
+			UserID:    user.ID,
+			UserEmail: user.EmailAddress,
+			Location:  location,
+			ExpiresAt: token.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+	fmt.Printf("The API token for '%s' has been rotated.\n", user.EmailAddress)
+	fmt.Printf("Saved to:   %s\n", location)
+	fmt.Printf("Expires:    %s\n", token.ExpiresAt.Local().Format(time.RFC1123))
+	return nil
+}
+
+// verifyToken makes sure the new token works and belongs to the same user as the old token.
+func (c *rotateCommand) verifyToken(token string, user *api.S1User) errorx.Error {
+	globalOpts := c.appState.Config().GlobalOptions()
+	s1Client := api.NewS1ClientBuilder(c.appState, globalOpts.TenantURL, token).
+		WithRetryPolicy(globalOpts.MaxRetries, globalOpts.RetryWait, globalOpts.RetryMaxWait).
+		WithTimeout(globalOpts.RequestTimeout).
+		Build()
+	newUser, errx := s1Client.GetCurrentUser()
+	if errx != nil {
+		return errx
+	}
+	if newUser.ID != user.ID {
+		errx := errors.NewS1ClientError("failed to verify new API token",
+			fmt.Errorf("token belongs to '%s' rather than '%s'", newUser.EmailAddress, user.EmailAddress))
+		c.appState.Logger().Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	return nil
+}
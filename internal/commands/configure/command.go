@@ -1,3 +1,332 @@
 package configure
 
-// TODO: This command will allow a user to interactively create a basic configuration file
+import (
+	goerrors "errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+	"go.joshhogle.dev/s1cli/internal/secrets"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+	prompter *prompt.Prompter
+}
+
+// NewCommand creates a new Command object.
+func NewCommand(state *app.State) *Command {
+	cmd := &Command{
+		appState: state,
+	}
+	cmd.Use = "configure"
+	cmd.Short = "Creates a configuration file."
+	cmd.Long = `This command is used to interactively create a basic configuration file.
+
+The tenant URL and API key are checked against the SentinelOne API before the file is written. Any existing
+configuration settings are offered as defaults. An existing file is only updated if --force is given, in which case
+the answers are merged into the file and any other settings and profiles it holds are kept.`
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().Configure().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *Command) runE(cmd *cobra.Command, args []string) error {
+	if err := c.appState.Initialize(&c.Command); err != nil {
+		return err
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Configure()
+	if err := cmdOpts.Load(); err != nil {
+		return err
+	}
+	cmdOpts.LogSettings(true)
+	logger := c.appState.Logger().With().Str("config_file", cmdOpts.OutputFile).Logger()
+
+	// the configuration can only be created interactively
+	if !prompt.IsInteractive() {
+		errx := errors.NewUsageError(goerrors.New("the configure command must be run from an interactive terminal"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// do not bother asking any questions if the file cannot be written
+	if _, err := os.Stat(cmdOpts.OutputFile); err == nil && !cmdOpts.Force {
+		errx := errors.NewConfigWriteFailure(cmdOpts.OutputFile,
+			goerrors.New("file already exists; use --force to update it"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// existing settings are offered as the defaults
+	globalOpts := c.appState.Config().GlobalOptions()
+	// the provisioning settings are only offered as defaults so settings which are not valid must not prevent
+	// the file from being created
+	accountOpts := c.appState.Config().CommandOptions().Provision().Account().Defaults()
+	c.prompter = prompt.NewPrompter(os.Stdin, os.Stdout)
+	settings := map[string]any{}
+	globalKey := globalOpts.ConfigKey()
+	accountKey := accountOpts.ConfigKey()
+
+	// ask for the global settings and check they work
+	fmt.Printf("\nGlobal settings (press ENTER to accept the value in brackets):\n\n")
+	tenantURL, apiKey := globalOpts.TenantURL, globalOpts.APIKey
+	for {
+		var errx errorx.Error
+		if tenantURL, errx = c.askString("Tenant URL (eg: https://my-tenant.sentinelone.net)", tenantURL,
+			validateTenantURL); errx != nil {
+			return errx
+		}
+		tenantURL = strings.TrimRight(tenantURL, "/")
+		if apiKey, errx = c.askAPIKey(apiKey); errx != nil {
+			return errx
+		}
+		if c.checkAPIKey(tenantURL, apiKey, globalOpts.RequestTimeout) {
+			break
+		}
+		retry, errx := c.askConfirm("Enter the tenant URL and API key again?", true)
+		if errx != nil {
+			return errx
+		}
+		if !retry {
+			save, errx := c.askConfirm("Save the configuration anyway?", false)
+			if errx != nil {
+				return errx
+			}
+			if !save {
+				logger.Info().Msg("configuration was not saved")
+				return nil
+			}
+			break
+		}
+	}
+	if secrets.Scheme(apiKey) == "" {
+		var errx errorx.Error
+		if apiKey, errx = c.storeAPIKey(apiKey); errx != nil {
+			return errx
+		}
+	}
+	settings[fmt.Sprintf("%s.tenant_url", globalKey)] = tenantURL
+	settings[fmt.Sprintf("%s.api_key", globalKey)] = apiKey
+	logLevel, errx := c.askString("Log level (trace, debug, info, warn, error)", globalOpts.LogLevel.String(),
+		validateLogLevel)
+	if errx != nil {
+		return errx
+	}
+	settings[fmt.Sprintf("%s.log_level", globalKey)] = logLevel
+
+	// ask for the defaults used when provisioning accounts
+	fmt.Printf("\nDefaults for provisioning accounts:\n\n")
+	for _, q := range []struct {
+		key        string
+		label      string
+		value      string
+		validateFn prompt.ValidateFn
+	}{
+		{"account_type", "Account type (Trial or Paid)", accountOpts.AccountType, app.ValidateAccountType},
+		{"role", "Role given to the first user", accountOpts.Role, validateNotEmpty("role")},
+		{"csv_separator", "CSV separator (a single character or 'tab')", csvSeparatorName(accountOpts.CSVSeparator),
+			validateCSVSeparator},
+	} {
+		answer, errx := c.askString(q.label, q.value, q.validateFn)
+		if errx != nil {
+			return errx
+		}
+		settings[fmt.Sprintf("%s.%s", accountKey, q.key)] = answer
+	}
+	concurrency, err := c.prompter.Int("Number of accounts to provision at the same time", accountOpts.Concurrency,
+		validateConcurrency)
+	if err != nil {
+		return c.readFailure(err)
+	}
+	settings[fmt.Sprintf("%s.concurrency", accountKey)] = concurrency
+	for _, q := range []struct {
+		key   string
+		label string
+		value bool
+	}{
+		{"reactivate_expired_account", "Reactivate existing accounts which have expired?",
+			accountOpts.ReactivateExpiredAccount},
+		{"reset_first_user_password", "Send the first user of each account a password reset e-mail?",
+			accountOpts.ResetFirstUserPassword},
+	} {
+		answer, errx := c.askConfirm(q.label, q.value)
+		if errx != nil {
+			return errx
+		}
+		settings[fmt.Sprintf("%s.%s", accountKey, q.key)] = answer
+	}
+
+	// write the file
+	if errx := c.appState.Config().WriteFile(cmdOpts.OutputFile, settings, cmdOpts.Force); errx != nil {
+		return errx
+	}
+	fmt.Printf("\nConfiguration has been saved to '%s'.\n", cmdOpts.OutputFile)
+	return nil
+}
+
+// askAPIKey asks the user for the API key without showing it as it is typed.
+//
+// The current key is never shown. Instead, the user can simply press ENTER to keep it.
+func (c *Command) askAPIKey(current string) (string, errorx.Error) {
+	label := "API key (or a file://, env://, cmd:// or keystore:// reference to it)"
+	if current != "" {
+		label = "API key (press ENTER to keep the current key)"
+	}
+	for {
+		answer, err := c.prompter.Secret(label)
+		if err != nil {
+			return "", c.readFailure(err)
+		}
+		if answer != "" {
+			return answer, nil
+		}
+		if current != "" {
+			return current, nil
+		}
+		fmt.Println("  API key cannot be empty")
+	}
+}
+
+// askConfirm asks the user a yes or no question.
+func (c *Command) askConfirm(question string, defaultAnswer bool) (bool, errorx.Error) {
+	answer, err := c.prompter.Confirm(question, defaultAnswer)
+	if err != nil {
+		return false, c.readFailure(err)
+	}
+	return answer, nil
+}
+
+// askString asks the user for a string value.
+func (c *Command) askString(question, defaultAnswer string, validateFn prompt.ValidateFn) (string, errorx.Error) {
+	answer, err := c.prompter.String(question, defaultAnswer, validateFn)
+	if err != nil {
+		return "", c.readFailure(err)
+	}
+	return answer, nil
+}
+
+// checkAPIKey makes a read-only call to the S1 API to make sure the tenant URL and API key are valid.
+func (c *Command) checkAPIKey(tenantURL, apiKey string, timeout time.Duration) bool {
+	fmt.Printf("\nChecking the API key with the tenant...\n")
+	apiKey, errx := c.appState.Config().GlobalOptions().ResolveSecret("api_key", apiKey)
+	if errx != nil {
+		fmt.Printf("  the check failed: %s\n\n", errx.Error())
+		return false
+	}
+	s1Client := api.NewS1ClientBuilder(c.appState, tenantURL, apiKey).
+		WithRetryPolicy(0, -1, -1).
+		WithTimeout(timeout).
+		Build()
+	user, errx := s1Client.GetCurrentUser()
+	if errx != nil {
+		fmt.Printf("  the check failed: %s\n\n", errx.Error())
+		return false
+	}
+	fmt.Printf("  the API key belongs to '%s'\n\n", user.EmailAddress)
+	return true
+}
+
+// storeAPIKey offers to store the API key in the encrypted keystore rather than in the configuration file.
+//
+// If the key is stored in the keystore, a reference to it is returned. Otherwise the key itself is returned.
+func (c *Command) storeAPIKey(apiKey string) (string, errorx.Error) {
+	store, errx := c.askConfirm("Store the API key in the encrypted keystore instead of the configuration file?", true)
+	if errx != nil || !store {
+		return apiKey, errx
+	}
+	name := c.appState.Config().GlobalOptions().Profile
+	if name == "" {
+		name = "default"
+	}
+	if name, errx = c.askString("Name of the API key in the keystore", name, validateNotEmpty("name")); errx != nil {
+		return "", errx
+	}
+	ks, errx := c.appState.Config().GlobalOptions().OpenKeystore()
+	if errx != nil {
+		return "", errx
+	}
+	err := ks.Set(name, apiKey)
+	if err == nil {
+		err = ks.Save()
+	}
+	if err != nil {
+		errx := errors.NewKeystoreFailure(ks.File(), err)
+		c.appState.Logger().Error().Err(errx).Msg(errx.Error())
+		return "", errx
+	}
+	return fmt.Sprintf("keystore://%s", name), nil
+}
+
+// readFailure returns the error used when an answer cannot be read.
+func (c *Command) readFailure(err error) errorx.Error {
+	errx := errors.NewGeneralFailure("failed to read answer", err)
+	c.appState.Logger().Error().Err(errx).Msg(errx.Error())
+	return errx
+}
+
+// csvSeparatorName returns the name used for the separator in the configuration file.
+func csvSeparatorName(separator string) string {
+	if separator == "\t" {
+		return "tab"
+	}
+	return separator
+}
+
+// validateConcurrency ensures the number of accounts provisioned at the same time is within the allowed range.
+func validateConcurrency(value string) error {
+	n, _ := strconv.Atoi(value)
+	return app.ValidateProvisionConcurrency(n)
+}
+
+// validateCSVSeparator ensures the separator is a single character or the word 'tab'.
+func validateCSVSeparator(value string) error {
+	if value != "tab" && len([]rune(value)) != 1 {
+		return goerrors.New("separator must be a single character or 'tab'")
+	}
+	return nil
+}
+
+// validateLogLevel ensures the log level is a valid level.
+func validateLogLevel(value string) error {
+	if _, err := zerolog.ParseLevel(value); err != nil || value == "" {
+		return fmt.Errorf("'%s' is not a valid log level", value)
+	}
+	return nil
+}
+
+// validateNotEmpty returns a function which ensures a value is not empty.
+func validateNotEmpty(name string) prompt.ValidateFn {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%s cannot be empty", name)
+		}
+		return nil
+	}
+}
+
+// validateTenantURL ensures the tenant URL is an HTTPS URL.
+func validateTenantURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return goerrors.New("tenant URL must be a URL beginning with https://")
+	}
+	return nil
+}
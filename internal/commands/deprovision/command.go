@@ -0,0 +1,458 @@
+package deprovision
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/csvfile"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// Types of objects which are changed.
+const (
+	typeAccount = "account"
+	typeUser    = "user"
+)
+
+// Actions reported for each account and user.
+const (
+	actionAlreadyExpired = "already expired"
+	actionDeleted        = "deleted"
+	actionExpired        = "expired"
+	actionFailed         = "failed"
+	actionKept           = "kept"
+	actionNotFound       = "not found"
+	actionRemoved        = "removed"
+	actionSkipped        = "skipped"
+	actionWouldDelete    = "would delete"
+	actionWouldExpire    = "would expire"
+	actionWouldRemove    = "would remove"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState      *app.State
+	accountExpire bool
+	s1Client      *api.S1Client
+}
+
+// accountRecord holds a single account to expire.
+//
+// The line is the line of the CSV file the account was read from or 0 if the account was given on the command-line.
+// Only the account columns of the CSV used to provision the accounts are read; all other columns are ignored.
+type accountRecord struct {
+	AccountID   string `csv:"account_id"`
+	AccountName string `csv:"account_name"`
+
+	// unexported variables
+	line int
+}
+
+// deprovisionResult holds the change made to a single account or user.
+type deprovisionResult struct {
+	Line   int    `csv:"line" json:"line" yaml:"line"`
+	Type   string `csv:"type" json:"type" yaml:"type"`
+	ID     string `csv:"id" json:"id" yaml:"id"`
+	Name   string `csv:"name" json:"name" yaml:"name"`
+	Action string `csv:"action" json:"action" yaml:"action"`
+	Detail string `csv:"detail" json:"detail,omitempty" yaml:"detail,omitempty"`
+	Error  string `csv:"error" json:"error,omitempty" yaml:"error,omitempty"`
+
+	// unexported variables
+	accountIDs []string
+	errx       errorx.Error
+	keepRoles  []api.S1UserScopeRole
+	pending    bool
+	wouldTake  string
+}
+
+// NewCommand creates a new Command object for the 'deprovision' command.
+func NewCommand(state *app.State) *Command {
+	cmd := newCommand(state, false)
+	cmd.Use = "deprovision"
+	cmd.Short = "Deprovisions accounts and users."
+	cmd.Long = `This command is used to shut down accounts on the SentinelOne platform once they are no longer needed.
+
+It does the same thing as the 'account expire' command and accepts the same CSV file used to provision the accounts.
+
+` + longDescription
+	return cmd
+}
+
+// NewAccountExpireCommand creates a new Command object for the 'account expire' command.
+func NewAccountExpireCommand(state *app.State) *Command {
+	cmd := newCommand(state, true)
+	cmd.Use = "expire"
+	cmd.Short = "Expires accounts and removes their users."
+	cmd.Long = `This command is used to expire accounts on the SentinelOne platform straight away.
+
+` + longDescription
+	return cmd
+}
+
+// longDescription describes how the 'deprovision' and 'account expire' commands behave.
+const longDescription = `Accounts may be given by ID or name using comma-separated lists or read from a CSV file. The CSV
+file may be the one used to provision the accounts: only the account_id and account_name columns are read and each row
+must set one of them.
+
+Once the accounts are expired, users who also belong to other accounts are removed from the expired accounts. Users
+who belong only to the expired accounts are kept unless --delete-users is given, in which case they are deleted. Users
+who are not account-level users are left alone. Use --keep-users to leave all users alone.
+
+Accounts which cannot be found or are already expired are reported but are not treated as failures so that the
+command can safely be run again.`
+
+// newCommand creates a new Command object with the given options.
+func newCommand(state *app.State, accountExpire bool) *Command {
+	cmd := &Command{
+		appState:      state,
+		accountExpire: accountExpire,
+	}
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	if accountExpire {
+		state.Config().CommandOptions().Account().Expire().BindFlags(&cmd.Command)
+	} else {
+		state.Config().CommandOptions().Deprovision().BindFlags(&cmd.Command)
+	}
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *Command) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().Deprovision()
+	if c.accountExpire {
+		cmdOpts = c.appState.Config().CommandOptions().Account().Expire()
+	}
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	logger := c.appState.Logger()
+
+	// gather the accounts given on the command-line and in the CSV
+	records := []accountRecord{}
+	for _, id := range csvfile.SplitList(cmdOpts.AccountIDs) {
+		records = append(records, accountRecord{AccountID: id})
+	}
+	for _, name := range csvfile.SplitList(cmdOpts.AccountNames) {
+		records = append(records, accountRecord{AccountName: name})
+	}
+	if cmdOpts.CSVSource != "" {
+		csvRecords, errx := csvfile.Read[accountRecord](c.appState, cmdOpts.CSVSource, cmdOpts.CSVSeparator)
+		if errx != nil {
+			return errx
+		}
+		for _, csvRecord := range csvRecords {
+			record := csvRecord.Value
+			record.line = csvRecord.Line
+			records = append(records, record)
+		}
+	}
+
+	builder, errx := api.NewS1ClientBuilderFromConfig(c.appState)
+	if errx != nil {
+		return errx
+	}
+	c.s1Client = builder.Build()
+
+	// work out every change first so that nothing is changed if the user decides not to go ahead
+	results, accountIDs := c.planAccounts(records)
+	if !cmdOpts.KeepUsers {
+		results = append(results, c.planUsers(accountIDs, cmdOpts.DeleteUsers)...)
+	}
+	pending := 0
+	planned := map[string]int{}
+	kept := 0
+	for _, result := range results {
+		if result.pending {
+			pending++
+			planned[result.wouldTake]++
+		}
+		if result.Action == actionKept {
+			kept++
+		}
+	}
+
+	// confirm the changes
+	if pending > 0 && !cmdOpts.DryRun && !cmdOpts.AssumeYes {
+		if !prompt.IsInteractive() {
+			errx := errors.NewUsageError(goerrors.New("use --yes to expire accounts when not running in a terminal"))
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		p := prompt.NewPrompter(os.Stdin, os.Stdout)
+		proceed, err := p.Confirm(fmt.Sprintf("Make %d change(s): %s?", pending, describeChanges(planned)), false)
+		if err != nil {
+			errx := errors.NewGeneralFailure("failed to read answer", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		if !proceed {
+			logger.Info().Msg("no accounts were expired")
+			return nil
+		}
+	}
+
+	// make the changes
+	// -- accounts are always expired before their users are changed and users are left alone if any of their
+	//    accounts could not be expired
+	var firstErr errorx.Error
+	failures := 0
+	failedAccounts := map[string]bool{}
+	for i := range results {
+		result := &results[i]
+		if result.pending {
+			switch {
+			case cmdOpts.DryRun:
+				result.Action = result.wouldTake
+			case slices.ContainsFunc(result.accountIDs, func(id string) bool { return failedAccounts[id] }):
+				result.Action = actionSkipped
+				result.Detail = "an account the user belongs to could not be expired"
+				logger.Warn().Str("user_id", result.ID).Msg("user was skipped because an account failed to expire")
+			default:
+				c.apply(result)
+			}
+		}
+		if result.Type == typeAccount && result.errx != nil {
+			failedAccounts[result.ID] = true
+		}
+		if result.errx != nil {
+			if firstErr == nil {
+				firstErr = result.errx
+			}
+			failures++
+		}
+	}
+	if errx := c.appState.Output().Write(results); errx != nil {
+		return errx
+	}
+	if firstErr != nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("%d of %d changes failed", failures, len(results)), firstErr)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	if !cmdOpts.DryRun {
+		logger.Info().Msg("all accounts have been expired")
+	}
+	if kept > 0 {
+		logger.Warn().Int("users", kept).
+			Msg("users who only belong to the expired accounts were kept and can still log in; " +
+				"use --delete-users to delete them")
+	}
+	return nil
+}
+
+// apply makes the change planned for the given result.
+func (c *Command) apply(result *deprovisionResult) {
+	logger := c.appState.Logger().With().Int("line", result.Line).Str("type", result.Type).Str("id", result.ID).
+		Str("name", result.Name).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+
+	var errx errorx.Error
+	var action string
+	switch result.wouldTake {
+	case actionWouldExpire:
+		errx = s1Client.ExpireAccount(result.ID)
+		action = actionExpired
+	case actionWouldDelete:
+		errx = s1Client.DeleteUser(result.ID)
+		action = actionDeleted
+	case actionWouldRemove:
+		_, errx = s1Client.UpdateUserScopeRoles(result.ID, result.keepRoles)
+		action = actionRemoved
+	}
+	if errx != nil {
+		result.setFailed(errx)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return
+	}
+	result.Action = action
+	logger.Info().Str("action", action).Msg("change has been made")
+}
+
+// planAccounts finds the accounts for the given records and works out which of them need to be expired.
+//
+// The IDs of all of the accounts which were found are returned along with the results so that their users can be
+// found.
+func (c *Command) planAccounts(records []accountRecord) ([]deprovisionResult, []string) {
+	results := []deprovisionResult{}
+	accountIDs := []string{}
+	seen := map[string]bool{}
+	for _, record := range records {
+		logger := c.appState.Logger().With().Int("line", record.line).Logger()
+		s1Client := c.s1Client.WithLogger(&logger)
+		result := deprovisionResult{
+			Line: record.line,
+			Type: typeAccount,
+			ID:   record.AccountID,
+			Name: record.AccountName,
+		}
+
+		// find the account
+		var account *api.S1Account
+		var errx errorx.Error
+		switch {
+		case record.AccountID != "":
+			account, errx = s1Client.FindAccountByID(record.AccountID)
+		case record.AccountName != "":
+			account, errx = s1Client.FindAccount(record.AccountName)
+		default:
+			errx = errors.NewUsageError(goerrors.New("an account ID or account name is required"))
+		}
+		if errx != nil {
+			result.setFailed(errx)
+			logger.Error().Err(errx).Msg(errx.Error())
+			results = append(results, result)
+			continue
+		}
+		if account == nil {
+			result.Action = actionNotFound
+			logger.Warn().Str("account_id", record.AccountID).Str("account_name", record.AccountName).
+				Msg("account was not found")
+			results = append(results, result)
+			continue
+		}
+		if seen[account.ID] {
+			continue
+		}
+		seen[account.ID] = true
+		accountIDs = append(accountIDs, account.ID)
+
+		// only active accounts need to be expired
+		result.ID = account.ID
+		result.Name = account.Name
+		if account.State == "active" {
+			result.pending = true
+			result.wouldTake = actionWouldExpire
+		} else {
+			result.Action = actionAlreadyExpired
+			result.Detail = fmt.Sprintf("account is %s", account.State)
+		}
+		results = append(results, result)
+	}
+	return results, accountIDs
+}
+
+// planUsers finds the users of the given accounts and works out how each of them needs to be changed.
+//
+// Users who also belong to other accounts only have the given accounts removed. Users who only belong to the given
+// accounts are deleted if deleteUsers is true and are otherwise kept. Users who are not account-level users are never
+// changed.
+func (c *Command) planUsers(accountIDs []string, deleteUsers bool) []deprovisionResult {
+	logger := c.appState.Logger()
+	expiring := map[string]bool{}
+	for _, id := range accountIDs {
+		expiring[id] = true
+	}
+
+	// accounts whose users cannot be listed are reported first so that any of their users planned for other accounts
+	// are skipped rather than changed
+	failures := []deprovisionResult{}
+	results := []deprovisionResult{}
+	seen := map[string]bool{}
+	for _, accountID := range accountIDs {
+		iter := c.s1Client.ListUsers(&api.S1ListOptions{
+			Filters: map[string]string{"accountIds": accountID},
+		})
+		accountResults := []deprovisionResult{}
+		for iter.Next() {
+			user := iter.Item()
+			if seen[user.ID] {
+				continue
+			}
+			if user.Scope != "account" {
+				logger.Debug().Str("user_id", user.ID).Str("scope", user.Scope).
+					Msg("leaving user alone as they are not an account-level user")
+				continue
+			}
+
+			// keep the roles for any accounts which are not being expired
+			result := deprovisionResult{
+				Type:    typeUser,
+				ID:      user.ID,
+				Name:    user.EmailAddress,
+				pending: true,
+			}
+			for _, role := range user.ScopeRoles {
+				if expiring[role.ScopeID] {
+					result.accountIDs = append(result.accountIDs, role.ScopeID)
+				} else {
+					result.keepRoles = append(result.keepRoles, role)
+				}
+			}
+			switch {
+			case len(result.keepRoles) == 0 && deleteUsers:
+				result.wouldTake = actionWouldDelete
+			case len(result.keepRoles) == 0:
+				result.Action = actionKept
+				result.Detail = "user only belongs to the expired accounts; use --delete-users to delete them"
+				result.pending = false
+			default:
+				result.wouldTake = actionWouldRemove
+				result.Detail = fmt.Sprintf("removed from accounts: %s", strings.Join(result.accountIDs, ", "))
+			}
+			accountResults = append(accountResults, result)
+		}
+
+		// users of an account which could only be partly listed are left alone
+		if errx := iter.Err(); errx != nil {
+			result := deprovisionResult{
+				Type: typeAccount,
+				ID:   accountID,
+			}
+			result.setFailed(errx)
+			logger.Error().Err(errx).Str("account_id", accountID).Msg("failed to list users for account")
+			failures = append(failures, result)
+			continue
+		}
+		for _, result := range accountResults {
+			seen[result.ID] = true
+		}
+		results = append(results, accountResults...)
+	}
+	return append(failures, results...)
+}
+
+// describeChanges describes the number of each kind of change which is about to be made.
+func describeChanges(planned map[string]int) string {
+	changes := []string{}
+	for _, change := range []struct {
+		action string
+		format string
+	}{
+		{actionWouldExpire, "expire %d account(s)"},
+		{actionWouldDelete, "delete %d user(s)"},
+		{actionWouldRemove, "remove %d user(s) from the expired accounts"},
+	} {
+		if count := planned[change.action]; count > 0 {
+			changes = append(changes, fmt.Sprintf(change.format, count))
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+// setFailed marks the result as failed with the given error.
+func (r *deprovisionResult) setFailed(errx errorx.Error) {
+	r.Action = actionFailed
+	r.Error = errx.Error()
+	r.errx = errx
+	r.pending = false
+}
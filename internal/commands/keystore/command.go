@@ -0,0 +1,64 @@
+package keystore
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/keystore"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// NewCommand creates a new Command object.
+func NewCommand(state *app.State) *Command {
+	cmd := &Command{
+		appState: state,
+	}
+	cmd.Use = "keystore"
+	cmd.Short = "Manages secrets in the encrypted keystore."
+	cmd.Long = `This command is used to manage secrets, such as API keys, in the local encrypted keystore.
+
+Secrets in the keystore are referred to in the configuration using keystore://<name>. The keystore passphrase is read
+from the S1CLI_KEYSTORE_PASSPHRASE environment variable or asked for when running in a terminal.`
+
+	// add flags
+	state.Config().CommandOptions().Keystore().BindFlags(&cmd.Command)
+
+	// add commands
+	cmd.AddCommand(&newGetCommand(state).Command)
+	cmd.AddCommand(&newListCommand(state).Command)
+	cmd.AddCommand(&newRemoveCommand(state).Command)
+	cmd.AddCommand(&newSetCommand(state).Command)
+
+	return cmd
+}
+
+// openKeystore loads the configuration for the given command and unlocks the keystore.
+func openKeystore(state *app.State, cmd *cobra.Command) (*keystore.Keystore, errorx.Error) {
+	if errx := state.Initialize(cmd); errx != nil {
+		return nil, errx
+	}
+	cmdOpts := state.Config().CommandOptions().Keystore()
+	if errx := cmdOpts.Load(); errx != nil {
+		return nil, errx
+	}
+	cmdOpts.LogSettings(true)
+	return state.Config().GlobalOptions().OpenKeystore()
+}
+
+// saveKeystore writes any changes to the keystore to its file.
+func saveKeystore(state *app.State, ks *keystore.Keystore) errorx.Error {
+	if err := ks.Save(); err != nil {
+		errx := errors.NewKeystoreFailure(ks.File(), err)
+		state.Logger().Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// getCommand is the object for executing the 'keystore get' command.
+type getCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newGetCommand creates a new getCommand object.
+func newGetCommand(state *app.State) *getCommand {
+	cmd := &getCommand{
+		appState: state,
+	}
+	cmd.Use = "get <name>"
+	cmd.Short = "Shows a secret stored in the keystore."
+	cmd.Long = `This command is used to write a secret stored in the keystore to standard output.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *getCommand) runE(cmd *cobra.Command, args []string) error {
+	ks, errx := openKeystore(c.appState, &c.Command)
+	if errx != nil {
+		return errx
+	}
+	secret, err := ks.Get(args[0])
+	if err != nil {
+		errx := errors.NewKeystoreFailure(ks.File(), err)
+		c.appState.Logger().Error().Err(errx).Str("name", args[0]).Msg(errx.Error())
+		return errx
+	}
+	fmt.Println(secret)
+	return nil
+}
@@ -0,0 +1,55 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// listCommand is the object for executing the 'keystore list' command.
+type listCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// secretRow holds the details of a single secret in the output.
+type secretRow struct {
+	Name string `csv:"name" json:"name" yaml:"name"`
+}
+
+// newListCommand creates a new listCommand object.
+func newListCommand(state *app.State) *listCommand {
+	cmd := &listCommand{
+		appState: state,
+	}
+	cmd.Use = "list"
+	cmd.Short = "Lists the secrets stored in the keystore."
+	cmd.Long = `This command is used to list the names of the secrets stored in the keystore.
+
+The secrets themselves are not shown.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *listCommand) runE(cmd *cobra.Command, args []string) error {
+	ks, errx := openKeystore(c.appState, &c.Command)
+	if errx != nil {
+		return errx
+	}
+	if output := c.appState.Output(); !output.IsTable() {
+		secrets := []secretRow{}
+		for _, name := range ks.Names() {
+			secrets = append(secrets, secretRow{Name: name})
+		}
+		return output.Write(secrets)
+	}
+	for _, name := range ks.Names() {
+		fmt.Println(name)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package keystore
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// removeCommand is the object for executing the 'keystore remove' command.
+type removeCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newRemoveCommand creates a new removeCommand object.
+func newRemoveCommand(state *app.State) *removeCommand {
+	cmd := &removeCommand{
+		appState: state,
+	}
+	cmd.Use = "remove <name>"
+	cmd.Short = "Removes a secret from the keystore."
+	cmd.Long = `This command is used to remove a secret from the keystore.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *removeCommand) runE(cmd *cobra.Command, args []string) error {
+	ks, errx := openKeystore(c.appState, &c.Command)
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("name", args[0]).Logger()
+	if err := ks.Remove(args[0]); err != nil {
+		errx := errors.NewKeystoreFailure(ks.File(), err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	if errx := saveKeystore(c.appState, ks); errx != nil {
+		return errx
+	}
+	logger.Info().Msg("secret has been removed from the keystore")
+	return nil
+}
@@ -0,0 +1,81 @@
+package keystore
+
+import (
+	goerrors "errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// setCommand is the object for executing the 'keystore set' command.
+type setCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newSetCommand creates a new setCommand object.
+func newSetCommand(state *app.State) *setCommand {
+	cmd := &setCommand{
+		appState: state,
+	}
+	cmd.Use = "set <name>"
+	cmd.Short = "Stores a secret in the keystore."
+	cmd.Long = `This command is used to store a secret in the keystore, replacing any existing secret with the same name.
+
+When running in a terminal, the secret is asked for without being shown. Otherwise it is read from standard input.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *setCommand) runE(cmd *cobra.Command, args []string) error {
+	ks, errx := openKeystore(c.appState, &c.Command)
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("name", args[0]).Logger()
+
+	// read the secret
+	var secret string
+	if prompt.IsInteractive() {
+		var err error
+		if secret, err = prompt.NewPrompter(os.Stdin, os.Stderr).Secret("Secret"); err != nil {
+			errx := errors.NewGeneralFailure("failed to read secret", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			errx := errors.NewGeneralFailure("failed to read secret from standard input", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		secret = strings.TrimRight(string(data), "\r\n")
+	}
+	if secret == "" {
+		errx := errors.NewUsageError(goerrors.New("secret cannot be empty"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// save the secret
+	if err := ks.Set(args[0], secret); err != nil {
+		errx := errors.NewKeystoreFailure(ks.File(), err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	if errx := saveKeystore(c.appState, ks); errx != nil {
+		return errx
+	}
+	logger.Info().Str("reference", "keystore://"+args[0]).Msg("secret has been saved to the keystore")
+	return nil
+}
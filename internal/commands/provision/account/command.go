@@ -1,18 +1,21 @@
 package account
 
 import (
-	"encoding/csv"
+	goerrors "errors"
 	"fmt"
-	"io"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
-	"github.com/jszwec/csvutil"
 	"github.com/spf13/cobra"
 	"go.joshhogle.dev/errorx"
 	"go.joshhogle.dev/s1cli/internal/api"
 	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/csvfile"
 	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/keystore"
 )
 
 // Command is the object for executing the actual command.
@@ -20,23 +23,58 @@ type Command struct {
 	cobra.Command
 
 	// unexported variables
-	appState *app.State
-	s1Client *api.S1Client
+	appState        *app.State
+	credentials     *keystore.Keystore
+	credentialsLock sync.Mutex
+	defaults        licenseDefaults
+	passwordPolicy  api.S1PasswordPolicy
+	s1Client        *api.S1Client
+	userLocks       sync.Map
+}
+
+// licenseDefaults holds the license details given to any account which does not supply its own.
+type licenseDefaults struct {
+	billingMode string
+	bundles     []api.S1LicenseBundle
+	inherits    bool
+	settings    map[string]string
+	usageType   string
+}
+
+// accountRecord holds a single account to provision along with one of its users.
+//
+// The line is the line of the CSV file the account was read from or 0 if the account was not read from a CSV.
+type accountRecord struct {
+	details accountDetails
+	line    int
+}
+
+// accountGroup holds every record for the same account.
+//
+// The account is created using the details in the first record, while each record adds a user to the account.
+type accountGroup struct {
+	records []accountRecord
 }
 
 // accountDetails holds the details for provisioning the account.
 type accountDetails struct {
-	AccountName  string `csv:"account_name"`
-	AccountType  string `csv:"account_type"`
-	Expires      string `csv:"expires"`
-	ExternalID   string `csv:"external_id"`
-	Bundle       string `csv:"bundle"`
-	TotalAgents  int    `csv:"total_agents"`
-	Modules      string `csv:"modules"`
-	FirstName    string `csv:"first_name"`
-	LastName     string `csv:"last_name"`
-	EmailAddress string `csv:"email_address"`
-	Role         string `csv:"role"`
+	AccountName     string `csv:"account_name"`
+	AccountType     string `csv:"account_type"`
+	Expires         string `csv:"expires"`
+	ExternalID      string `csv:"external_id"`
+	Bundle          string `csv:"bundle"`
+	TotalAgents     string `csv:"total_agents"`
+	Modules         string `csv:"modules"`
+	FirstName       string `csv:"first_name"`
+	LastName        string `csv:"last_name"`
+	EmailAddress    string `csv:"email_address"`
+	Role            string `csv:"role"`
+	ResetPassword   string `csv:"reset_password"`
+	BillingMode     string `csv:"billing_mode"`
+	UsageType       string `csv:"usage_type"`
+	Inherits        string `csv:"inherits"`
+	Bundles         string `csv:"bundles"`
+	LicenseSettings string `csv:"license_settings"`
 }
 
 // NewCommand creates a new Command object.
@@ -67,95 +105,479 @@ func (c *Command) runE(cmd *cobra.Command, args []string) error {
 	cmdOpts.LogSettings(true)
 	logger := c.appState.Logger()
 
-	// TODO: check API key and tenant URL
-	globalOpts := c.appState.Config().GlobalOptions()
-	c.s1Client = api.NewS1ClientBuilder(c.appState, globalOpts.TenantURL, globalOpts.APIKey).Build()
+	builder, errx := api.NewS1ClientBuilderFromConfig(c.appState)
+	if errx != nil {
+		return errx
+	}
+	c.s1Client = builder.Build()
+
+	// make sure the license defaults are usable before reading any accounts
+	bundles, err := api.ParseLicenseBundles(cmdOpts.Bundles)
+	if err != nil {
+		errx := errors.NewUsageError(fmt.Errorf("--bundles: %w", err))
+		logger.Error().Err(errx).Str("bundles", cmdOpts.Bundles).Msg(errx.Error())
+		return errx
+	}
+	c.defaults = licenseDefaults{
+		billingMode: cmdOpts.BillingMode,
+		bundles:     bundles,
+		inherits:    cmdOpts.Inherits,
+		settings:    cmdOpts.LicenseSettings,
+		usageType:   cmdOpts.UsageType,
+	}
+
+	if c.passwordPolicy, err = api.NewPasswordPolicy(cmdOpts.PasswordLength, cmdOpts.PasswordClasses); err != nil {
+		errx := errors.NewUsageError(fmt.Errorf("--password-classes: %w", err))
+		logger.Error().Err(errx).Str("password_classes", cmdOpts.PasswordClasses).Msg(errx.Error())
+		return errx
+	}
 
+	// read the accounts from the CSV or, if no CSV was given, from flags and prompts
+	var records []accountRecord
 	if cmdOpts.CSVSource == "" {
-		// TODO: if no CSV has been provided, prompt for the information to provision the account
-		fmt.Printf("\n\n-- Only CSV provisioning is supported at this time --\n\n")
-		return nil
+		account, proceed, errx := c.completeAccountDetails(accountDetails{
+			AccountName:  cmdOpts.AccountName,
+			AccountType:  cmdOpts.AccountType,
+			Expires:      cmdOpts.Expires,
+			ExternalID:   cmdOpts.ExternalID,
+			Bundle:       cmdOpts.Bundle,
+			TotalAgents:  formatTotalAgents(cmdOpts.TotalAgents),
+			Modules:      cmdOpts.Modules,
+			FirstName:    cmdOpts.FirstName,
+			LastName:     cmdOpts.LastName,
+			EmailAddress: cmdOpts.EmailAddress,
+			Role:         cmdOpts.Role,
+			Bundles:      cmdOpts.Bundles,
+		}, cmdOpts.AssumeYes || cmdOpts.DryRun)
+		if errx != nil {
+			return errx
+		}
+		if !proceed {
+			logger.Info().Msg("account provisioning was cancelled")
+			return nil
+		}
+		records = []accountRecord{{details: account}}
+	} else {
+		var nameTmpl *accountNameTemplate
+		if cmdOpts.AccountNameFormat != "" {
+			if nameTmpl, err = newAccountNameTemplate(cmdOpts.AccountNameFormat); err != nil {
+				errx := errors.NewUsageError(fmt.Errorf("--account-name-format: %w", err))
+				logger.Error().Err(errx).Str("account_name_format", cmdOpts.AccountNameFormat).Msg(errx.Error())
+				return errx
+			}
+		}
+		defaults := accountDetails{
+			AccountType: cmdOpts.AccountType,
+			Role:        cmdOpts.Role,
+		}
+		if records, errx = c.readRecords(cmdOpts.CSVSource, cmdOpts.CSVSeparator, nameTmpl,
+			defaults); errx != nil {
+			return errx
+		}
 	}
 
-	// open the CSV
-	f, err := os.Open(cmdOpts.CSVSource)
-	if err != nil {
-		errx := errors.NewGeneralFailure(
-			fmt.Sprintf("failed to open CSV file '%s' for reading", cmdOpts.CSVSource), err)
-		logger.Error().Err(errx).Str("csv_file", cmdOpts.CSVSource).Msg(errx.Error())
+	// make sure every record is valid before making any changes
+	groups := groupRecords(records)
+	if errx := c.validateRecords(groups, len(records)); errx != nil {
 		return errx
 	}
 
-	// read the CSV
-	csvReader := csv.NewReader(f)
-	csvReader.Comma = rune(cmdOpts.CSVSeparator[0])
-	dec, err := csvutil.NewDecoder(csvReader)
-	if err != nil {
-		errx := errors.NewGeneralFailure(
-			fmt.Sprintf("failed to parse CSV file '%s'", cmdOpts.CSVSource), err)
-		logger.Error().Err(errx).Str("csv_file", cmdOpts.CSVSource).Msg(errx.Error())
+	// show what would be done without making any changes
+	if cmdOpts.DryRun {
+		return c.planAccounts(groups, cmdOpts.Concurrency, cmdOpts.ReactivateExpiredAccount,
+			cmdOpts.ResetFirstUserPassword)
+	}
+
+	// make sure the API token can still be used before making any changes; this is skipped for a dry run since
+	// checking the token is itself a POST request
+	if errx := c.checkAPIToken(); errx != nil {
 		return errx
 	}
 
-	// provision the list of accounts
-	for {
-		var account accountDetails
-		if err := dec.Decode(&account); err == io.EOF {
-			logger.Info().Msg("all accounts have been provisioned")
-			break
-		} else if err != nil {
-			errx := errors.NewGeneralFailure("failed to decode account record", err)
-			logger.Error().Err(errx).Str("csv_file", cmdOpts.CSVSource).Msg(errx.Error())
+	// unlock the credentials file before making any changes so that no generated password is lost
+	if cmdOpts.CredentialsFile != "" {
+		if c.credentials, errx = c.appState.Config().GlobalOptions().OpenKeystoreFile(
+			cmdOpts.CredentialsFile); errx != nil {
 			return errx
 		}
+	}
 
-		if err := c.provisionAccount(account, cmdOpts.ReactivateExpiredAccount,
-			cmdOpts.ResetFirstUserPassword); err != nil {
-			return err
+	// provision the list of accounts using a pool of workers
+	// -- unless we are continuing on error, no new accounts are started once an account fails to provision but
+	//    those already in progress are allowed to finish
+	groupResults := make([][]provisionResult, len(groups))
+	forEachRecord(len(groups), cmdOpts.Concurrency, !cmdOpts.ContinueOnError, func(index int) bool {
+		groupResults[index] = c.provisionAccount(groups[index], cmdOpts.ReactivateExpiredAccount,
+			cmdOpts.ResetFirstUserPassword)
+		for _, result := range groupResults[index] {
+			if result.errx != nil {
+				return false
+			}
 		}
+		return true
+	}, func(index int) {
+		for _, record := range groups[index].records {
+			groupResults[index] = append(groupResults[index], newSkippedResult(record))
+		}
+	})
+
+	// report the results in the order the records appear in the file
+	results := make([]provisionResult, 0, len(records))
+	for _, group := range groupResults {
+		results = append(results, group...)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+	var firstErr errorx.Error
+	failures := 0
+	for _, result := range results {
+		rowLogger := logger.With().Int("line", result.Line).Str("account_name", result.AccountName).Logger()
+		switch result.Action {
+		case actionSkipped:
+			rowLogger.Warn().Msg("account was skipped because a previous account failed to provision")
+		case actionFailed:
+			rowLogger.Error().Err(result.errx).Msg("account failed to provision")
+			if firstErr == nil {
+				firstErr = result.errx
+			}
+			failures++
+		default:
+			rowLogger.Info().Str("account_id", result.AccountID).Str("action", result.Action).
+				Str("user_id", result.UserID).Str("user_action", result.UserAction).Msg("account was provisioned")
+		}
+	}
+	if errx := c.appState.Output().Write(results); errx != nil {
+		return errx
+	}
+	if cmdOpts.ResultsFile != "" {
+		if errx := writeResults(cmdOpts.ResultsFile, cmdOpts.ResultsFormat, results); errx != nil {
+			logger.Error().Err(errx).Str("results_file", cmdOpts.ResultsFile).Msg(errx.Error())
+			return errx
+		}
+		logger.Info().Str("results_file", cmdOpts.ResultsFile).Msg("results have been saved")
+	}
+	if firstErr != nil {
+		if !cmdOpts.ContinueOnError {
+			return firstErr
+		}
+		errx := errors.NewProvisionFailure(failures, len(results), firstErr)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
 	}
+	logger.Info().Msg("all accounts have been provisioned")
 	return nil
 }
 
-func (c *Command) provisionAccount(account accountDetails, reactivate, resetFirstUserPass bool) errorx.Error {
-	// TODO: add checks for request values
+// checkAPIToken makes sure the API token has not expired, warning if it is about to expire.
+//
+// Some API tokens are not allowed to read their own details. Rather than stopping, a warning is logged and the
+// provisioning requests themselves are left to fail if the token cannot be used.
+func (c *Command) checkAPIToken() errorx.Error {
+	_, errx := c.s1Client.CheckAPIToken(c.appState.Config().GlobalOptions().TokenExpiryWarning)
+	if errx == nil {
+		return nil
+	}
+	if apiErr, ok := errx.(*errors.S1APIError); ok && !apiErr.IsUnauthorized() {
+		c.appState.Logger().Warn().Err(errx).Msg("unable to check when the API token expires")
+		return nil
+	}
+	return errx
+}
 
-	// create the account
-	acct, errx := c.s1Client.CreateAccount(api.S1AccountProvisioningRequest{
-		AccountName:       account.AccountName,
-		AccountType:       account.AccountType,
-		Expires:           account.Expires,
-		ExternalID:        account.ExternalID,
-		ReactivateAccount: reactivate,
-		Bundle:            account.Bundle,
-		Modules:           strings.Split(account.Modules, ","),
-		TotalAgents:       account.TotalAgents,
-	})
+// readRecords reads all of the accounts from the given CSV file.
+//
+// If nameTmpl is not nil, it is used to name any account which does not have an account name. Any user without a role
+// is given the default role. The first record for each account is given the default account type if it does not
+// have one; later records for the same account may still leave it empty.
+func (c *Command) readRecords(csvFile, separator string, nameTmpl *accountNameTemplate, defaults accountDetails) (
+	[]accountRecord, errorx.Error) {
+
+	csvRecords, errx := csvfile.Read[accountDetails](c.appState, csvFile, separator)
 	if errx != nil {
+		return nil, errx
+	}
+	records := make([]accountRecord, 0, len(csvRecords))
+	seen := map[string]bool{}
+	for i, csvRecord := range csvRecords {
+		account := csvRecord.Value
+		if account.AccountName == "" && nameTmpl != nil {
+			name, err := nameTmpl.Execute(i+1, csvRecord.Columns)
+			if err != nil {
+				errx := errors.NewGeneralFailure(fmt.Sprintf("failed to build account name on line %d",
+					csvRecord.Line), err)
+				c.appState.Logger().Error().Err(errx).Str("csv_file", csvFile).Int("line", csvRecord.Line).
+					Msg(errx.Error())
+				return nil, errx
+			}
+			account.AccountName = name
+		}
+		if account.Role == "" {
+			account.Role = defaults.Role
+		}
+		if key := accountKey(account.AccountName); !seen[key] {
+			seen[key] = true
+			if account.AccountType == "" {
+				account.AccountType = defaults.AccountType
+			}
+		}
+		records = append(records, accountRecord{
+			details: account,
+			line:    csvRecord.Line,
+		})
+	}
+	return records, nil
+}
+
+// accountKey returns the key used to match records for the same account, ignoring differences in case.
+func accountKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// groupRecords combines the records for the same account, ignoring differences in case.
+//
+// Groups are returned in the order their accounts first appear.
+func groupRecords(records []accountRecord) []accountGroup {
+	groups := []accountGroup{}
+	index := map[string]int{}
+	for _, record := range records {
+		name := accountKey(record.details.AccountName)
+		if i, ok := index[name]; ok {
+			groups[i].records = append(groups[i].records, record)
+			continue
+		}
+		index[name] = len(groups)
+		groups = append(groups, accountGroup{records: []accountRecord{record}})
+	}
+	return groups
+}
+
+// forEachRecord calls fn for each record index using a pool of concurrent workers.
+//
+// If stopOnFailure is true and fn returns false, no new records are started but those already in progress are
+// allowed to finish. skipFn is called for each record which is never started.
+func forEachRecord(count, concurrency int, stopOnFailure bool, fn func(int) bool, skipFn func(int)) {
+	jobs := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, count); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if !fn(index) {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	for index := 0; index < count; index++ {
+		if stopOnFailure && failed.Load() {
+			skipFn(index)
+			continue
+		}
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// lockUser prevents other workers from modifying the user with the given e-mail address until the returned function
+// is called.
+//
+// Adding a user to an account rewrites the user's complete list of scope roles, so two workers adding the same user
+// to different accounts at the same time could otherwise overwrite each other's changes.
+func (c *Command) lockUser(email string) func() {
+	mutex, _ := c.userLocks.LoadOrStore(strings.ToLower(email), &sync.Mutex{})
+	mutex.(*sync.Mutex).Lock()
+	return mutex.(*sync.Mutex).Unlock
+}
+
+// saveCredentials stores the password of a new user in the credentials file using the user's e-mail address as the
+// name of the secret.
+//
+// The file is saved after each password is added so that passwords are not lost if provisioning is interrupted.
+//
+// The following errors are returned by this function:
+// KeystoreFailure
+func (c *Command) saveCredentials(email, passwd string) errorx.Error {
+	c.credentialsLock.Lock()
+	defer c.credentialsLock.Unlock()
+
+	err := c.credentials.Set(strings.ToLower(email), passwd)
+	if err == nil {
+		err = c.credentials.Save()
+	}
+	if err != nil {
+		errx := errors.NewKeystoreFailure(c.credentials.File(), err)
+		c.appState.Logger().Error().Err(errx).Str("email_address", email).Msg(errx.Error())
 		return errx
 	}
-	logger := c.appState.Logger().With().Str("account_id", acct.ID).Str("account_name", acct.Name).Logger()
+	return nil
+}
+
+// provisionAccount creates the account and adds each of its users, returning the result for each record.
+//
+// If the account cannot be created, every record fails. Otherwise a user which cannot be added only fails its own
+// record.
+func (c *Command) provisionAccount(group accountGroup, reactivate, resetPassword bool) []provisionResult {
+	account := group.records[0].details
+	logger := c.appState.Logger().With().Int("line", group.records[0].line).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+	results := make([]provisionResult, 0, len(group.records))
+	failAll := func(errx errorx.Error) []provisionResult {
+		for _, record := range group.records {
+			results = append(results, newResult(record).failed(errx))
+		}
+		return results
+	}
+
+	// create the account
+	req, err := c.newProvisioningRequest(account, reactivate)
+	if err != nil {
+		errx := errors.NewGeneralFailure("invalid license details for account", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return failAll(errx)
+	}
+	acct, action, errx := s1Client.CreateAccount(req)
+	if errx != nil {
+		return failAll(errx)
+	}
+	logger = logger.With().Str("account_id", acct.ID).Str("account_name", acct.Name).Logger()
 	logger.Info().Msg("account has been successfully provisioned")
 
+	// add the users
+	for _, record := range group.records {
+		result := newResult(record)
+		result.AccountID = acct.ID
+		result.Action = string(action)
+		results = append(results, c.provisionUser(record, acct.ID, resetPassword, result))
+	}
+	return results
+}
+
+// provisionUser creates the user in the given record, or adds an existing user, to the account.
+//
+// The user is sent a password reset e-mail if the record's reset_password column is true or, when the column is
+// empty, if resetPassword is true.
+func (c *Command) provisionUser(record accountRecord, accountID string, resetPassword bool,
+	result provisionResult) provisionResult {
+
+	account := record.details
+	logger := c.appState.Logger().With().Int("line", record.line).Str("account_id", accountID).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+
 	// create the user
-	user, errx := c.s1Client.CreateUser(&api.S1UserProvisioningRequest{
+	passwd, err := c.passwordPolicy.Generate()
+	if err != nil {
+		errx := errors.NewGeneralFailure("failed to generate password for user", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return result.failed(errx)
+	}
+	unlock := c.lockUser(account.EmailAddress)
+	user, userAction, errx := s1Client.CreateUser(&api.S1UserProvisioningRequest{
 		FirstName:    account.FirstName,
 		LastName:     account.LastName,
 		EmailAddress: account.EmailAddress,
 		Role:         account.Role,
-	}, acct.ID)
+		Password:     passwd,
+	}, accountID)
+	unlock()
 	if errx != nil {
-		return errx
+		return result.failed(errx)
 	}
+	result.UserID = user.ID
+	result.UserAction = string(userAction)
 	logger = logger.With().Str("user_id", user.ID).Str("email_address", user.EmailAddress).Logger()
 	logger.Info().Msg("user has been created and enabled for account")
 
+	// save the password of a new user
+	if userAction == api.S1ProvisioningActionCreated && c.credentials != nil {
+		if errx := c.saveCredentials(account.EmailAddress, passwd); errx != nil {
+			return result.failed(errx)
+		}
+		logger.Info().Str("credentials_file", c.credentials.File()).Msg("user password has been saved")
+	}
+
 	// reset the user's password
-	if resetFirstUserPass {
-		if errx := c.s1Client.ResetUserPassword(user.ID); errx != nil {
-			return errx
+	if account.ResetPassword != "" {
+		resetPassword, _ = strconv.ParseBool(account.ResetPassword)
+	}
+	if resetPassword {
+		if errx := s1Client.ResetUserPassword(user.ID); errx != nil {
+			return result.failed(errx)
 		}
 	}
+	return result
+}
 
-	return nil
+// newProvisioningRequest builds the request used to create the account.
+//
+// Any license details the account does not supply are taken from the defaults. License settings given for the
+// account are merged over the default settings. If the account has no bundles of its own, the bundle and total
+// number of agents are used before falling back to the default bundles.
+func (c *Command) newProvisioningRequest(account accountDetails, reactivate bool) (
+	api.S1AccountProvisioningRequest, error) {
+
+	req := api.S1AccountProvisioningRequest{
+		AccountName:       account.AccountName,
+		AccountType:       account.AccountType,
+		BillingMode:       c.defaults.billingMode,
+		Expires:           account.Expires,
+		ExternalID:        account.ExternalID,
+		Inherits:          c.defaults.inherits,
+		ReactivateAccount: reactivate,
+		UsageType:         c.defaults.usageType,
+		Modules:           splitModules(account.Modules),
+	}
+	if account.BillingMode != "" {
+		req.BillingMode = account.BillingMode
+	}
+	if account.UsageType != "" {
+		req.UsageType = account.UsageType
+	}
+	if account.Inherits != "" {
+		inherits, err := strconv.ParseBool(account.Inherits)
+		if err != nil {
+			return req, fmt.Errorf("inherits must be true or false, not '%s'", account.Inherits)
+		}
+		req.Inherits = inherits
+	}
+
+	// license bundles
+	switch {
+	case account.Bundles != "":
+		bundles, err := api.ParseLicenseBundles(account.Bundles)
+		if err != nil {
+			return req, err
+		}
+		req.Bundles = bundles
+	case account.Bundle != "":
+		// the number of agents has already been validated
+		totalAgents, _ := strconv.Atoi(account.TotalAgents)
+		req.Bundles = []api.S1LicenseBundle{{
+			Name:     account.Bundle,
+			Surfaces: []api.S1LicenseSurface{{Name: "Total Agents", Count: totalAgents}},
+		}}
+	default:
+		req.Bundles = c.defaults.bundles
+	}
+	if len(req.Bundles) == 0 {
+		return req, goerrors.New("at least one license bundle is required")
+	}
+
+	// license settings
+	settings, err := api.ParseLicenseSettings(account.LicenseSettings)
+	if err != nil {
+		return req, err
+	}
+	merged := make(map[string]string, len(c.defaults.settings)+len(settings))
+	for group, setting := range c.defaults.settings {
+		merged[group] = setting
+	}
+	for group, setting := range settings {
+		merged[group] = setting
+	}
+	req.Settings = api.NewLicenseSettings(merged)
+	return req, nil
 }
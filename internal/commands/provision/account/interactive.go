@@ -0,0 +1,109 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// accountField describes a single account detail which may be supplied using a flag or prompted for.
+type accountField struct {
+	flag       string
+	label      string
+	value      *string
+	validateFn prompt.ValidateFn
+}
+
+// completeAccountDetails fills in any account details which were not supplied using flags.
+//
+// When standard input is a terminal and any required detail is missing or invalid, the user is asked for every
+// detail in turn with the supplied values offered as defaults. A summary is then shown and the user must confirm it
+// unless assumeYes is true. When standard input is not a terminal, every required detail must already be valid.
+//
+// The returned boolean is false if the user chose not to provision the account.
+func (c *Command) completeAccountDetails(account accountDetails, assumeYes bool) (
+	accountDetails, bool, errorx.Error) {
+
+	logger := c.appState.Logger()
+	fields := []accountField{
+		{flag: "account-name", label: "Account name", value: &account.AccountName, validateFn: validateAccountName},
+		{flag: "account-type", label: "Account type (Trial or Paid)", value: &account.AccountType,
+			validateFn: app.ValidateAccountType},
+		{flag: "expires", label: "Expires (duration or RFC3339 date)", value: &account.Expires,
+			validateFn: validateExpires},
+		{flag: "external-id", label: "External ID (optional)", value: &account.ExternalID},
+		{flag: "modules", label: "License modules (comma-separated, optional)", value: &account.Modules},
+	}
+	if account.Bundles == "" {
+		// bundles given with --bundles already include the number of agents
+		fields = append(fields, []accountField{
+			{flag: "bundle", label: "License bundle", value: &account.Bundle, validateFn: validateBundle},
+			{flag: "total-agents", label: "Total agents", value: &account.TotalAgents,
+				validateFn: validateTotalAgents},
+		}...)
+	}
+	fields = append(fields, []accountField{
+		{flag: "first-name", label: "First name of user", value: &account.FirstName, validateFn: validateFirstName},
+		{flag: "last-name", label: "Last name of user", value: &account.LastName, validateFn: validateLastName},
+		{flag: "email-address", label: "E-mail address of user", value: &account.EmailAddress,
+			validateFn: validateEmailAddress},
+		{flag: "role", label: "Role of user", value: &account.Role, validateFn: validateRole},
+	}...)
+
+	// find any details which still need to be supplied
+	problems := []string{}
+	for _, field := range fields {
+		if field.validateFn == nil {
+			continue
+		}
+		if err := field.validateFn(*field.value); err != nil {
+			problems = append(problems, fmt.Sprintf("--%s: %s", field.flag, err.Error()))
+		}
+	}
+	interactive := prompt.IsInteractive()
+	if len(problems) > 0 && !interactive {
+		errx := errors.NewUsageError(fmt.Errorf(
+			"missing or invalid account details; use --csv-source or supply the following flags:\n  %s",
+			strings.Join(problems, "\n  ")))
+		logger.Error().Err(errx).Strs("problems", problems).Msg("missing or invalid account details")
+		return account, false, errx
+	}
+
+	// ask for each detail
+	p := prompt.NewPrompter(os.Stdin, os.Stdout)
+	if len(problems) > 0 {
+		fmt.Printf("\nEnter the details for the new account (press ENTER to accept the value in brackets):\n\n")
+		for _, field := range fields {
+			answer, err := p.String(field.label, *field.value, field.validateFn)
+			if err != nil {
+				errx := errors.NewGeneralFailure("failed to read account details", err)
+				logger.Error().Err(errx).Str("field", field.flag).Msg(errx.Error())
+				return account, false, errx
+			}
+			*field.value = answer
+		}
+	}
+
+	if assumeYes || !interactive {
+		return account, true, nil
+	}
+
+	// show a summary and confirm before making any changes
+	fmt.Printf("\nThe following account will be provisioned:\n\n")
+	for _, field := range fields {
+		fmt.Printf("  %-45s %s\n", field.label+":", *field.value)
+	}
+	fmt.Println()
+	proceed, err := p.Confirm("Provision this account?", false)
+	if err != nil {
+		errx := errors.NewGeneralFailure("failed to read confirmation", err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return account, false, errx
+	}
+	return account, proceed, nil
+}
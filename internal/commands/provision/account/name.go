@@ -0,0 +1,75 @@
+package account
+
+import (
+	goerrors "errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// slugPattern matches any run of characters which are not allowed in a slug.
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// accountNameTemplate builds account names from the columns of a CSV record.
+type accountNameTemplate struct {
+	seq  int
+	tmpl *template.Template
+}
+
+// newAccountNameTemplate parses the account name format.
+//
+// The format is a Go template which can refer to any CSV column by name (eg: {{.company}}) along with the following
+// functions:
+//
+//	lower    converts the value to lower case
+//	upper    converts the value to upper case
+//	trim     removes leading and trailing whitespace from the value
+//	slug     converts the value to lower case words separated by dashes
+//	pad      left-pads the value with zeros to the given width (eg: {{.id | pad 3}})
+//	default  uses the given value if the value is empty (eg: {{.company | default "Workshop"}})
+//	seq      returns the sequence number of the record starting at 1
+func newAccountNameTemplate(format string) (*accountNameTemplate, error) {
+	t := &accountNameTemplate{}
+	tmpl, err := template.New("account_name_format").Option("missingkey=error").Funcs(template.FuncMap{
+		"default": func(def, value string) string {
+			if value == "" {
+				return def
+			}
+			return value
+		},
+		"lower": strings.ToLower,
+		"pad": func(width int, value any) string {
+			return fmt.Sprintf("%0*s", width, fmt.Sprint(value))
+		},
+		"seq": func() int {
+			return t.seq
+		},
+		"slug": func(value string) string {
+			return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(value), "-"), "-")
+		},
+		"trim":  strings.TrimSpace,
+		"upper": strings.ToUpper,
+	}).Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Execute builds the name of an account from the given CSV columns.
+//
+// The sequence number is the position of the record in the file starting at 1.
+func (t *accountNameTemplate) Execute(seq int, columns map[string]string) (string, error) {
+	t.seq = seq
+	buf := &strings.Builder{}
+	if err := t.tmpl.Execute(buf, columns); err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(buf.String())
+	if name == "" {
+		return "", goerrors.New("account name format produced an empty account name")
+	}
+	return name, nil
+}
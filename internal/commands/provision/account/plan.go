@@ -0,0 +1,204 @@
+package account
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// accountPlan holds the changes that would be made in order to provision a single account.
+type accountPlan struct {
+	Line        int       `csv:"line" json:"line" yaml:"line"`
+	AccountName string    `csv:"account_name" json:"account_name" yaml:"account_name"`
+	Steps       planItems `csv:"steps" json:"steps" yaml:"steps"`
+	Problems    planItems `csv:"problems" json:"problems" yaml:"problems"`
+
+	// unexported variables
+	errx errorx.Error
+}
+
+// planItems holds the steps or problems in a plan.
+type planItems []string
+
+// MarshalCSV joins the items into a single CSV field.
+func (p planItems) MarshalCSV() ([]byte, error) {
+	return []byte(strings.Join(p, "; ")), nil
+}
+
+// planAccount determines what would be done to provision the account and each of its users.
+//
+// Only read-only API calls are made so nothing is changed on the server.
+func (c *Command) planAccount(group accountGroup, reactivate, resetPassword bool) accountPlan {
+	record := group.records[0]
+	account := record.details
+	logger := c.appState.Logger().With().Int("line", record.line).Logger()
+	s1Client := c.s1Client.WithLogger(&logger)
+	plan := accountPlan{
+		AccountName: account.AccountName,
+		Line:        record.line,
+		Problems:    []string{},
+		Steps:       []string{},
+	}
+
+	// check the expiration
+	expiration := account.Expires
+	if expires, err := api.ParseExpiration(account.Expires); err == nil {
+		expiration = expires.Format(time.RFC3339)
+	} else {
+		plan.Problems = append(plan.Problems, fmt.Sprintf("expiration '%s' is not a valid duration or RFC3339 date",
+			account.Expires))
+	}
+
+	// check the license details
+	req, err := c.newProvisioningRequest(account, reactivate)
+	if err != nil {
+		plan.Problems = append(plan.Problems, fmt.Sprintf("invalid license details: %s", err.Error()))
+	}
+	bundles := make([]string, 0, len(req.Bundles))
+	for _, bundle := range req.Bundles {
+		bundles = append(bundles, bundle.String())
+	}
+
+	// check the account
+	acct, errx := s1Client.FindAccount(account.AccountName)
+	if errx != nil {
+		plan.errx = errx
+		return plan
+	}
+	switch {
+	case acct == nil:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("create %s account expiring %s with bundles '%s'",
+			account.AccountType, expiration, strings.Join(bundles, ";")))
+	case acct.State == "active":
+		plan.Steps = append(plan.Steps, fmt.Sprintf("use existing active account %s expiring %s", acct.ID,
+			acct.Expiration.Format(time.RFC3339)))
+	case acct.State == "expired" && reactivate:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("reactivate expired account %s until %s", acct.ID,
+			expiration))
+	case acct.State == "expired":
+		plan.Problems = append(plan.Problems, fmt.Sprintf(
+			"account %s is expired and expired accounts are not set to be reactivated", acct.ID))
+	default:
+		plan.Problems = append(plan.Problems, fmt.Sprintf("account %s exists and is currently '%s'", acct.ID,
+			acct.State))
+	}
+
+	// check the users
+	for _, record := range group.records {
+		if errx := c.planUser(&plan, s1Client, acct, record.details, resetPassword); errx != nil {
+			plan.errx = errx
+			return plan
+		}
+	}
+	return plan
+}
+
+// planUser adds the steps needed to add the given user to the account to the plan.
+//
+// The account is nil if it does not exist yet.
+func (c *Command) planUser(plan *accountPlan, s1Client *api.S1Client, acct *api.S1Account, account accountDetails,
+	resetPassword bool) errorx.Error {
+
+	user, errx := s1Client.FindUser(account.EmailAddress)
+	if errx != nil {
+		return errx
+	}
+	switch {
+	case user == nil:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("create user %s with role '%s'", account.EmailAddress,
+			account.Role))
+	case acct != nil && userHasScope(user, acct.ID):
+		plan.Steps = append(plan.Steps, fmt.Sprintf("use existing user %s who already has access to the account",
+			user.ID))
+	default:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("add existing user %s to the account with role '%s'", user.ID,
+			account.Role))
+	}
+
+	// make sure the role exists in an existing account
+	if acct != nil {
+		role, errx := s1Client.FindRole(acct.ID, account.Role)
+		if errx != nil {
+			return errx
+		}
+		if role == nil {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("role '%s' does not exist in the account",
+				account.Role))
+		}
+	}
+
+	if account.ResetPassword != "" {
+		resetPassword, _ = strconv.ParseBool(account.ResetPassword)
+	}
+	if resetPassword {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("send a password reset e-mail to %s", account.EmailAddress))
+	}
+	return nil
+}
+
+// planAccounts prints what would be done to provision each of the accounts without making any changes.
+//
+// The following errors are returned by this function:
+// ProvisionFailure, S1APIError, S1ClientError, S1ClientRequestError
+func (c *Command) planAccounts(groups []accountGroup, concurrency int, reactivate, resetPassword bool) errorx.Error {
+	plans := make([]accountPlan, len(groups))
+	forEachRecord(len(groups), concurrency, true, func(index int) bool {
+		plans[index] = c.planAccount(groups[index], reactivate, resetPassword)
+		return plans[index].errx == nil
+	}, func(index int) {
+		plans[index] = accountPlan{
+			AccountName: groups[index].records[0].details.AccountName,
+			Line:        groups[index].records[0].line,
+			Steps:       planItems{},
+			Problems:    planItems{"skipped because a previous lookup failed"},
+		}
+	})
+
+	// show the plan for each account in the order they appear in the file
+	problems := 0
+	buf := &strings.Builder{}
+	for _, plan := range plans {
+		if plan.errx != nil {
+			return plan.errx
+		}
+		if len(plan.Problems) > 0 {
+			problems++
+		}
+		fmt.Fprintf(buf, "line %d: %s\n", plan.Line, plan.AccountName)
+		for _, step := range plan.Steps {
+			fmt.Fprintf(buf, "  - %s\n", step)
+		}
+		for _, problem := range plan.Problems {
+			fmt.Fprintf(buf, "  ! %s\n", problem)
+		}
+	}
+	if output := c.appState.Output(); output.IsTable() {
+		fmt.Printf("%s\n", buf.String())
+	} else if errx := output.Write(plans); errx != nil {
+		return errx
+	}
+
+	if problems > 0 {
+		errx := errors.NewProvisionFailure(problems, len(plans),
+			fmt.Errorf("%d accounts cannot be provisioned as planned", problems))
+		c.appState.Logger().Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	c.appState.Logger().Info().Msg("dry run complete ; no changes were made")
+	return nil
+}
+
+// userHasScope returns whether or not the user has a role in the given account.
+func userHasScope(user *api.S1User, accountID string) bool {
+	for _, role := range user.ScopeRoles {
+		if role.ScopeID == accountID {
+			return true
+		}
+	}
+	return false
+}
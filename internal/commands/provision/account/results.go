@@ -0,0 +1,92 @@
+package account
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jszwec/csvutil"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// Row actions which are not the result of an API call.
+const (
+	actionFailed  = "failed"
+	actionSkipped = "skipped"
+)
+
+// provisionResult holds the outcome of provisioning a single account and one of its users.
+type provisionResult struct {
+	Line         int    `csv:"line" json:"line" yaml:"line"`
+	AccountName  string `csv:"account_name" json:"account_name" yaml:"account_name"`
+	AccountID    string `csv:"account_id" json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	Action       string `csv:"action" json:"action" yaml:"action"`
+	EmailAddress string `csv:"email_address" json:"email_address" yaml:"email_address"`
+	UserID       string `csv:"user_id" json:"user_id,omitempty" yaml:"user_id,omitempty"`
+	UserAction   string `csv:"user_action" json:"user_action,omitempty" yaml:"user_action,omitempty"`
+	Error        string `csv:"error" json:"error,omitempty" yaml:"error,omitempty"`
+
+	// unexported variables
+	errx errorx.Error
+}
+
+// newResult returns an empty result for the given record.
+func newResult(record accountRecord) provisionResult {
+	return provisionResult{
+		Line:         record.line,
+		AccountName:  record.details.AccountName,
+		EmailAddress: record.details.EmailAddress,
+	}
+}
+
+// newSkippedResult returns the result for an account which was never provisioned.
+func newSkippedResult(record accountRecord) provisionResult {
+	result := newResult(record)
+	result.Action = actionSkipped
+	return result
+}
+
+// failed marks the result as failed due to the given error and returns it.
+func (r provisionResult) failed(errx errorx.Error) provisionResult {
+	r.Action = actionFailed
+	r.Error = errx.Error()
+	r.errx = errx
+	return r
+}
+
+// writeResults saves the results to the given file in either CSV or JSON format.
+//
+// The file may contain e-mail addresses so it is only readable by the current user.
+//
+// The following errors are returned by this function:
+// GeneralFailure
+func writeResults(file, format string, results []provisionResult) errorx.Error {
+	var data []byte
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return errors.NewGeneralFailure("failed to convert results to JSON", err)
+		}
+		data = append(output, '\n')
+	default:
+		buf := &bytes.Buffer{}
+		w := csv.NewWriter(buf)
+		if err := csvutil.NewEncoder(w).Encode(results); err != nil {
+			return errors.NewGeneralFailure("failed to convert results to CSV", err)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return errors.NewGeneralFailure("failed to convert results to CSV", err)
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return errors.NewGeneralFailure(fmt.Sprintf("failed to write results file '%s'", file), err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package account
+
+import (
+	goerrors "errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// recordCheck holds a single value of a record to validate.
+type recordCheck struct {
+	column     string
+	value      string
+	validateFn func(string) error
+}
+
+// validateAccountName ensures the account name is not empty.
+func validateAccountName(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return goerrors.New("account name cannot be empty")
+	}
+	return nil
+}
+
+// validateBundle ensures the license bundle is not empty.
+func validateBundle(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return goerrors.New("bundle cannot be empty")
+	}
+	return nil
+}
+
+// validateEmailAddress ensures the value is a plain e-mail address.
+func validateEmailAddress(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return fmt.Errorf("'%s' is not a valid e-mail address", value)
+	}
+	return nil
+}
+
+// validateExpires ensures the expiration is either a duration or an RFC3339 date in the future.
+func validateExpires(value string) error {
+	expires, err := api.ParseExpiration(value)
+	if err != nil {
+		return goerrors.New("expiration must be a duration (eg: 720h) or an RFC3339 date")
+	}
+	if !expires.After(time.Now()) {
+		return goerrors.New("expiration must be in the future")
+	}
+	return nil
+}
+
+// validateFirstName ensures the first name of the user is not empty.
+func validateFirstName(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return goerrors.New("first name cannot be empty")
+	}
+	return nil
+}
+
+// validateLastName ensures the last name of the user is not empty.
+func validateLastName(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return goerrors.New("last name cannot be empty")
+	}
+	return nil
+}
+
+// validateResetPassword ensures the reset password flag is either empty or a boolean value.
+func validateResetPassword(value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("reset password must be true or false, not '%s'", value)
+	}
+	return nil
+}
+
+// validateRole ensures the role name is not empty.
+func validateRole(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return goerrors.New("role cannot be empty")
+	}
+	return nil
+}
+
+// validateTotalAgents ensures the number of agents is a positive whole number.
+func validateTotalAgents(value string) error {
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return goerrors.New("total number of agents must be a whole number greater than 0")
+	}
+	return nil
+}
+
+// validateRecords checks every record before any accounts are provisioned.
+//
+// Rather than stopping at the first invalid value, every record is checked so that all of the problems can be
+// reported at once. The account details are checked in the first record of each group. Later records for the same
+// account may leave the account details empty but may not change them. A user may be added to more than one account
+// but only once to the same account.
+//
+// The following errors are returned by this function:
+// RecordValidateFailure
+func (c *Command) validateRecords(groups []accountGroup, total int) errorx.Error {
+	problems := []string{}
+	invalid := 0
+	for _, group := range groups {
+		first := group.records[0]
+		users := map[string]int{}
+		for i, record := range group.records {
+			account := record.details
+			location := "account"
+			if record.line > 0 {
+				location = fmt.Sprintf("line %d", record.line)
+			}
+			recordProblems := []string{}
+			addProblem := func(column string, err error) {
+				recordProblems = append(recordProblems, fmt.Sprintf("%s: %s: %s", location, column, err.Error()))
+			}
+
+			// check the individual values
+			checks := []recordCheck{
+				{column: "first_name", value: account.FirstName, validateFn: validateFirstName},
+				{column: "last_name", value: account.LastName, validateFn: validateLastName},
+				{column: "email_address", value: account.EmailAddress, validateFn: validateEmailAddress},
+				{column: "role", value: account.Role, validateFn: validateRole},
+				{column: "reset_password", value: account.ResetPassword, validateFn: validateResetPassword},
+			}
+			if i == 0 {
+				checks = append(checks, []recordCheck{
+					{column: "account_name", value: account.AccountName, validateFn: validateAccountName},
+					{column: "account_type", value: account.AccountType, validateFn: app.ValidateAccountType},
+					{column: "expires", value: account.Expires, validateFn: validateExpires},
+				}...)
+				if account.Bundles == "" && account.Bundle != "" {
+					checks = append(checks, recordCheck{column: "total_agents", value: account.TotalAgents,
+						validateFn: validateTotalAgents})
+				}
+			}
+			for _, check := range checks {
+				if err := check.validateFn(check.value); err != nil {
+					addProblem(check.column, err)
+				}
+			}
+			if i == 0 {
+				if _, err := c.newProvisioningRequest(account, false); err != nil {
+					addProblem("license", err)
+				}
+			} else {
+				for _, column := range conflictingColumns(first.details, account) {
+					addProblem(column, fmt.Errorf("conflicts with the value for account '%s' on line %d",
+						first.details.AccountName, first.line))
+				}
+			}
+
+			// check for the same user more than once
+			email := strings.ToLower(account.EmailAddress)
+			if line, ok := users[email]; ok && email != "" {
+				addProblem("email_address", fmt.Errorf("user '%s' is also added to account '%s' on line %d",
+					account.EmailAddress, first.details.AccountName, line))
+			} else {
+				users[email] = record.line
+			}
+
+			if len(recordProblems) > 0 {
+				invalid++
+				problems = append(problems, recordProblems...)
+			}
+		}
+	}
+	if invalid == 0 {
+		return nil
+	}
+	errx := errors.NewRecordValidateFailure(invalid, total, problems)
+	c.appState.Logger().Error().Err(errx).Msg("one or more records are invalid")
+	return errx
+}
+
+// conflictingColumns returns the account columns which are set in the later record but differ from the first record
+// for the same account.
+func conflictingColumns(first, later accountDetails) []string {
+	columns := []string{}
+	for _, column := range []struct {
+		name         string
+		first, later string
+	}{
+		{name: "account_type", first: first.AccountType, later: later.AccountType},
+		{name: "expires", first: first.Expires, later: later.Expires},
+		{name: "external_id", first: first.ExternalID, later: later.ExternalID},
+		{name: "bundle", first: first.Bundle, later: later.Bundle},
+		{name: "total_agents", first: first.TotalAgents, later: later.TotalAgents},
+		{name: "modules", first: first.Modules, later: later.Modules},
+		{name: "billing_mode", first: first.BillingMode, later: later.BillingMode},
+		{name: "usage_type", first: first.UsageType, later: later.UsageType},
+		{name: "inherits", first: first.Inherits, later: later.Inherits},
+		{name: "bundles", first: first.Bundles, later: later.Bundles},
+		{name: "license_settings", first: first.LicenseSettings, later: later.LicenseSettings},
+	} {
+		if column.later != "" && column.later != column.first {
+			columns = append(columns, column.name)
+		}
+	}
+	return columns
+}
+
+// formatTotalAgents converts the total number of agents into a string, leaving it empty if it was not given.
+func formatTotalAgents(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return strconv.Itoa(count)
+}
+
+// splitModules converts a comma-separated list of modules into a list, ignoring any empty entries.
+func splitModules(value string) []string {
+	modules := []string{}
+	for _, module := range strings.Split(value, ",") {
+		if module = strings.TrimSpace(module); module != "" {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
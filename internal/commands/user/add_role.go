@@ -0,0 +1,102 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// addRoleCommand is the object for executing the 'user add-role' command.
+type addRoleCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newAddRoleCommand creates a new addRoleCommand object.
+func newAddRoleCommand(state *app.State) *addRoleCommand {
+	cmd := &addRoleCommand{
+		appState: state,
+	}
+	cmd.Use = "add-role <email-or-id>"
+	cmd.Short = "Gives a user a role in an account."
+	cmd.Long = `This command is used to give an existing account-level user a role in an account.
+
+A user has a single role in each account, so if the user already has a different role in the account, that role is
+replaced.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().User().AddRole().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *addRoleCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User().AddRole()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	user, errx := findUser(c.appState, s1Client, args[0])
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("user_id", user.ID).Str("email_address", user.EmailAddress).Logger()
+	if user.Scope != "account" {
+		errx := errors.NewUsageError(fmt.Errorf("user has %s scope and cannot be given account roles", user.Scope))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	account, errx := findAccount(c.appState, s1Client, cmdOpts.AccountID, cmdOpts.AccountName)
+	if errx != nil {
+		return errx
+	}
+	logger = logger.With().Str("account_id", account.ID).Logger()
+	role, errx := s1Client.FindRole(account.ID, cmdOpts.Role)
+	if errx != nil {
+		return errx
+	}
+	if role == nil {
+		errx := errors.NewUsageError(fmt.Errorf("role '%s' does not exist in account '%s'", cmdOpts.Role,
+			account.Name))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+
+	// replace any role the user already has in the account
+	roles := []api.S1UserScopeRole{}
+	for _, scopeRole := range user.ScopeRoles {
+		if scopeRole.ScopeID != account.ID {
+			roles = append(roles, scopeRole)
+		} else if scopeRole.RoleID == role.ID {
+			logger.Info().Str("role", role.Name).Msg("user already has the role in the account")
+			return c.appState.Output().Write(newUserRow(user))
+		}
+	}
+	roles = append(roles, api.S1UserScopeRole{
+		ScopeID:  account.ID,
+		RoleID:   role.ID,
+		RoleName: role.Name,
+	})
+	user, errx = s1Client.UpdateUserScopeRoles(user.ID, roles)
+	if errx != nil {
+		return errx
+	}
+	logger.Info().Str("role", role.Name).Msg("user has been given the role in the account")
+	return c.appState.Output().Write(newUserRow(user))
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// Command is the object for executing the actual command.
+type Command struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// userRow holds the details of a single user in the output.
+type userRow struct {
+	ID              string `csv:"id" json:"id" yaml:"id"`
+	EmailAddress    string `csv:"email_address" json:"email_address" yaml:"email_address"`
+	FullName        string `csv:"full_name" json:"full_name" yaml:"full_name"`
+	Scope           string `csv:"scope" json:"scope" yaml:"scope"`
+	Roles           string `csv:"roles" json:"roles" yaml:"roles"`
+	EmailVerified   bool   `csv:"email_verified" json:"email_verified" yaml:"email_verified"`
+	TwoFactorStatus string `csv:"two_factor_status" json:"two_factor_status" yaml:"two_factor_status"`
+}
+
+// NewCommand creates a new Command object.
+func NewCommand(state *app.State) *Command {
+	cmd := &Command{
+		appState: state,
+	}
+	cmd.Use = "user"
+	cmd.Short = "Manages users."
+	cmd.Long = `This command is used to look at and manage users on the SentinelOne platform.
+
+Commands which act on an existing user accept either the user's e-mail address or ID.`
+
+	// add flags
+	state.Config().CommandOptions().User().BindFlags(&cmd.Command)
+
+	// add commands
+	cmd.AddCommand(&newAddRoleCommand(state).Command)
+	cmd.AddCommand(&newCreateCommand(state).Command)
+	cmd.AddCommand(&newDeleteCommand(state).Command)
+	cmd.AddCommand(&newGetCommand(state).Command)
+	cmd.AddCommand(&newListCommand(state).Command)
+	cmd.AddCommand(&newRemoveRoleCommand(state).Command)
+	cmd.AddCommand(&newResetPasswordCommand(state).Command)
+
+	return cmd
+}
+
+// newS1Client returns a client for the S1 API using the loaded configuration.
+func newS1Client(state *app.State) (*api.S1Client, errorx.Error) {
+	builder, errx := api.NewS1ClientBuilderFromConfig(state)
+	if errx != nil {
+		return nil, errx
+	}
+	return builder.Build(), nil
+}
+
+// findAccount returns the account with the given ID or, if no ID is given, the given name.
+//
+// Unlike the client's own search functions, an error is returned if the account does not exist.
+func findAccount(state *app.State, s1Client *api.S1Client, id, name string) (*api.S1Account, errorx.Error) {
+	var account *api.S1Account
+	var errx errorx.Error
+	ref := id
+	if id != "" {
+		account, errx = s1Client.FindAccountByID(id)
+	} else {
+		ref = name
+		account, errx = s1Client.FindAccount(name)
+	}
+	if errx != nil {
+		return nil, errx
+	}
+	if account == nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("account '%s' was not found", ref),
+			goerrors.New("no matching accounts"))
+		state.Logger().Error().Err(errx).Str("account", ref).Msg(errx.Error())
+		return nil, errx
+	}
+	return account, nil
+}
+
+// findUser returns the user with the given e-mail address or ID.
+//
+// Unlike the client's own search functions, an error is returned if the user does not exist.
+func findUser(state *app.State, s1Client *api.S1Client, ref string) (*api.S1User, errorx.Error) {
+	var user *api.S1User
+	var errx errorx.Error
+	if strings.Contains(ref, "@") {
+		user, errx = s1Client.FindUser(ref)
+	} else {
+		user, errx = s1Client.FindUserByID(ref)
+	}
+	if errx != nil {
+		return nil, errx
+	}
+	if user == nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("user '%s' was not found", ref), goerrors.New("no matching users"))
+		state.Logger().Error().Err(errx).Str("user", ref).Msg(errx.Error())
+		return nil, errx
+	}
+	return user, nil
+}
+
+// newUserRow returns the output row for the given user.
+//
+// Each of the user's roles is shown as the ID of the scope the role applies to followed by the name of the role.
+func newUserRow(user *api.S1User) userRow {
+	roles := make([]string, 0, len(user.ScopeRoles))
+	for _, role := range user.ScopeRoles {
+		roles = append(roles, fmt.Sprintf("%s:%s", role.ScopeID, role.RoleName))
+	}
+	return userRow{
+		ID:              user.ID,
+		EmailAddress:    user.EmailAddress,
+		FullName:        user.FullName,
+		Scope:           user.Scope,
+		Roles:           strings.Join(roles, ", "),
+		EmailVerified:   user.EmailVerified,
+		TwoFactorStatus: user.TwoFactorStatus,
+	}
+}
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// createCommand is the object for executing the 'user create' command.
+type createCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newCreateCommand creates a new createCommand object.
+func newCreateCommand(state *app.State) *createCommand {
+	cmd := &createCommand{
+		appState: state,
+	}
+	cmd.Use = "create"
+	cmd.Short = "Creates a user."
+	cmd.Long = `This command is used to create a new account-level user on the SentinelOne platform.
+
+The user is given the role set by --role in the account set by --account-id or --account-name. If a user with the
+same e-mail address already exists, that user is added to the account instead.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().User().Create().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *createCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User().Create()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	account, errx := findAccount(c.appState, s1Client, cmdOpts.AccountID, cmdOpts.AccountName)
+	if errx != nil {
+		return errx
+	}
+	user, action, errx := s1Client.CreateUser(&api.S1UserProvisioningRequest{
+		FirstName:    cmdOpts.FirstName,
+		LastName:     cmdOpts.LastName,
+		EmailAddress: cmdOpts.EmailAddress,
+		Role:         cmdOpts.Role,
+	}, account.ID)
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("user_id", user.ID).Str("email_address", user.EmailAddress).
+		Str("account_id", account.ID).Logger()
+	logger.Info().Str("action", string(action)).Msg("user has been provisioned")
+
+	if cmdOpts.ResetPassword {
+		if errx := s1Client.ResetUserPassword(user.ID); errx != nil {
+			return errx
+		}
+		logger.Info().Msg("password reset email has been sent")
+	}
+	return c.appState.Output().Write(newUserRow(user))
+}
@@ -0,0 +1,99 @@
+package user
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+	"go.joshhogle.dev/s1cli/internal/prompt"
+)
+
+// deleteCommand is the object for executing the 'user delete' command.
+type deleteCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newDeleteCommand creates a new deleteCommand object.
+func newDeleteCommand(state *app.State) *deleteCommand {
+	cmd := &deleteCommand{
+		appState: state,
+	}
+	cmd.Use = "delete <email-or-id>..."
+	cmd.Short = "Deletes users."
+	cmd.Long = `This command is used to delete users from the SentinelOne platform.
+
+All of the users are looked up before any of them are deleted so that nothing is deleted if any of them cannot be
+found. To take a user out of a single account instead, use the 'user remove-role' command.`
+	cmd.Args = cobra.MinimumNArgs(1)
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().User().Delete().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *deleteCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User().Delete()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	logger := c.appState.Logger()
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	// find all of the users first
+	users := []*api.S1User{}
+	emails := []string{}
+	for _, ref := range args {
+		user, errx := findUser(c.appState, s1Client, ref)
+		if errx != nil {
+			return errx
+		}
+		users = append(users, user)
+		emails = append(emails, user.EmailAddress)
+	}
+
+	// deleting users cannot be undone so give the user a chance to back out
+	if !cmdOpts.AssumeYes {
+		if !prompt.IsInteractive() {
+			errx := errors.NewUsageError(goerrors.New("use --yes to delete users when not running in a terminal"))
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		p := prompt.NewPrompter(os.Stdin, os.Stdout)
+		proceed, err := p.Confirm(fmt.Sprintf("Delete %s? This cannot be undone.", strings.Join(emails, ", ")), false)
+		if err != nil {
+			errx := errors.NewGeneralFailure("failed to read answer", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return errx
+		}
+		if !proceed {
+			logger.Info().Msg("no users were deleted")
+			return nil
+		}
+	}
+
+	for _, user := range users {
+		if errx := s1Client.DeleteUser(user.ID); errx != nil {
+			return errx
+		}
+		logger.Info().Str("user_id", user.ID).Str("email_address", user.EmailAddress).Msg("user has been deleted")
+	}
+	return nil
+}
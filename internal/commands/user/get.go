@@ -0,0 +1,49 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// getCommand is the object for executing the 'user get' command.
+type getCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newGetCommand creates a new getCommand object.
+func newGetCommand(state *app.State) *getCommand {
+	cmd := &getCommand{
+		appState: state,
+	}
+	cmd.Use = "get <email-or-id>"
+	cmd.Short = "Shows a user."
+	cmd.Long = `This command is used to show the details of a single user, including the roles the user has.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *getCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	user, errx := findUser(c.appState, s1Client, args[0])
+	if errx != nil {
+		return errx
+	}
+	return c.appState.Output().Write(newUserRow(user))
+}
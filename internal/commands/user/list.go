@@ -0,0 +1,72 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// listCommand is the object for executing the 'user list' command.
+type listCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newListCommand creates a new listCommand object.
+func newListCommand(state *app.State) *listCommand {
+	cmd := &listCommand{
+		appState: state,
+	}
+	cmd.Use = "list"
+	cmd.Short = "Lists users."
+	cmd.Long = `This command is used to list the users on the SentinelOne platform.
+
+All users are listed unless filters are given. When more than one filter is given, only users matching all of them are
+listed.`
+	cmd.Args = cobra.NoArgs
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().User().List().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *listCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User().List()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	filters := map[string]string{}
+	if cmdOpts.AccountID != "" {
+		filters["accountIds"] = cmdOpts.AccountID
+	}
+	if cmdOpts.Email != "" {
+		filters["email__contains"] = cmdOpts.Email
+	}
+	rows := []userRow{}
+	iter := s1Client.ListUsers(&api.S1ListOptions{
+		Filters: filters,
+	})
+	for iter.Next() {
+		rows = append(rows, newUserRow(iter.Item()))
+	}
+	if errx := iter.Err(); errx != nil {
+		return errx
+	}
+	c.appState.Logger().Debug().Int("users", len(rows)).Msg("listed users")
+
+	return c.appState.Output().Write(rows)
+}
@@ -0,0 +1,87 @@
+package user
+
+import (
+	goerrors "errors"
+
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/api"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// removeRoleCommand is the object for executing the 'user remove-role' command.
+type removeRoleCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newRemoveRoleCommand creates a new removeRoleCommand object.
+func newRemoveRoleCommand(state *app.State) *removeRoleCommand {
+	cmd := &removeRoleCommand{
+		appState: state,
+	}
+	cmd.Use = "remove-role <email-or-id>"
+	cmd.Short = "Removes a user's role in an account."
+	cmd.Long = `This command is used to take an account-level user out of an account by removing their role in it.
+
+A user must always belong to at least one account, so the user's last role cannot be removed. Use the 'user delete'
+command instead.`
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = cmd.runE
+
+	// add flags
+	state.Config().CommandOptions().User().RemoveRole().BindFlags(&cmd.Command)
+
+	return cmd
+}
+
+// run simply executes the command.
+func (c *removeRoleCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User().RemoveRole()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	user, errx := findUser(c.appState, s1Client, args[0])
+	if errx != nil {
+		return errx
+	}
+	account, errx := findAccount(c.appState, s1Client, cmdOpts.AccountID, cmdOpts.AccountName)
+	if errx != nil {
+		return errx
+	}
+	logger := c.appState.Logger().With().Str("user_id", user.ID).Str("email_address", user.EmailAddress).
+		Str("account_id", account.ID).Logger()
+
+	roles := []api.S1UserScopeRole{}
+	for _, scopeRole := range user.ScopeRoles {
+		if scopeRole.ScopeID != account.ID {
+			roles = append(roles, scopeRole)
+		}
+	}
+	if len(roles) == len(user.ScopeRoles) {
+		logger.Info().Msg("user does not have a role in the account")
+		return c.appState.Output().Write(newUserRow(user))
+	}
+	if len(roles) == 0 {
+		errx := errors.NewUsageError(goerrors.New("cannot remove the user's only role; use 'user delete' instead"))
+		logger.Error().Err(errx).Msg(errx.Error())
+		return errx
+	}
+	user, errx = s1Client.UpdateUserScopeRoles(user.ID, roles)
+	if errx != nil {
+		return errx
+	}
+	logger.Info().Msg("user's role in the account has been removed")
+	return c.appState.Output().Write(newUserRow(user))
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"go.joshhogle.dev/s1cli/internal/app"
+)
+
+// resetPasswordCommand is the object for executing the 'user reset-password' command.
+type resetPasswordCommand struct {
+	cobra.Command
+
+	// unexported variables
+	appState *app.State
+}
+
+// newResetPasswordCommand creates a new resetPasswordCommand object.
+func newResetPasswordCommand(state *app.State) *resetPasswordCommand {
+	cmd := &resetPasswordCommand{
+		appState: state,
+	}
+	cmd.Use = "reset-password <email-or-id>..."
+	cmd.Short = "Sends users a password reset email."
+	cmd.Long = `This command is used to send each of the given users an email which lets them choose a new password.`
+	cmd.Args = cobra.MinimumNArgs(1)
+	cmd.RunE = cmd.runE
+	return cmd
+}
+
+// run simply executes the command.
+func (c *resetPasswordCommand) runE(cmd *cobra.Command, args []string) error {
+	if errx := c.appState.Initialize(&c.Command); errx != nil {
+		return errx
+	}
+	cmdOpts := c.appState.Config().CommandOptions().User()
+	if errx := cmdOpts.Load(); errx != nil {
+		return errx
+	}
+	cmdOpts.LogSettings(true)
+	s1Client, errx := newS1Client(c.appState)
+	if errx != nil {
+		return errx
+	}
+
+	for _, ref := range args {
+		user, errx := findUser(c.appState, s1Client, ref)
+		if errx != nil {
+			return errx
+		}
+		if errx := s1Client.ResetUserPassword(user.ID); errx != nil {
+			return errx
+		}
+		c.appState.Logger().Info().Str("user_id", user.ID).Str("email_address", user.EmailAddress).
+			Msg("password reset email has been sent")
+	}
+	return nil
+}
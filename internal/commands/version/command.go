@@ -17,6 +17,15 @@ type Command struct {
 	appState *app.State
 }
 
+// versionInfo holds the version details written when the output is meant for scripts.
+type versionInfo struct {
+	Title            string `csv:"title" json:"title" yaml:"title"`
+	Version          string `csv:"version" json:"version" yaml:"version"`
+	Build            string `csv:"build" json:"build" yaml:"build"`
+	CodeName         string `csv:"code_name" json:"code_name" yaml:"code_name"`
+	IsDeveloperBuild bool   `csv:"developer_build" json:"developer_build" yaml:"developer_build"`
+}
+
 // NewCommand creates a new Command object.
 func NewCommand(state *app.State) *Command {
 	cmd := &Command{
@@ -44,6 +53,17 @@ func (c *Command) runE(cmd *cobra.Command, args []string) error {
 	productInfo := c.appState.ProductInfo()
 	c.appState.DisableLogger(true)
 
+	// scripts get every detail in the chosen format
+	if output := c.appState.Output(); !output.IsTable() {
+		return output.Write(versionInfo{
+			Title:            productInfo.Title,
+			Version:          productInfo.Version.String(),
+			Build:            productInfo.Build,
+			CodeName:         productInfo.CodeName,
+			IsDeveloperBuild: productInfo.IsDeveloperBuild,
+		})
+	}
+
 	// show just the version
 	if cmdOpts.Short {
 		fmt.Printf("%s\n", productInfo.Version.String())
@@ -0,0 +1,90 @@
+package csvfile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jszwec/csvutil"
+	"go.joshhogle.dev/errorx"
+	"go.joshhogle.dev/s1cli/internal/app"
+	"go.joshhogle.dev/s1cli/internal/errors"
+)
+
+// Record holds a single record decoded from a CSV file.
+type Record[T any] struct {
+	// Value holds the decoded record.
+	Value T
+
+	// Line is the line in the file where the record starts.
+	Line int
+
+	// Columns maps the name of each column in the header to its value in the record.
+	Columns map[string]string
+}
+
+// Read decodes all of the records in the given CSV file.
+//
+// The first line of the file must be a header naming the columns, which are matched to the `csv` tags of T. All of
+// the records are read up front so that they can be checked before any changes are made and so that results can be
+// reported in the same order as the file.
+//
+// The following errors are returned by this function:
+// GeneralFailure
+func Read[T any](state *app.State, file, separator string) ([]Record[T], errorx.Error) {
+	logger := state.Logger().With().Str("csv_file", file).Logger()
+
+	// open the CSV
+	f, err := os.Open(file)
+	if err != nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("failed to open CSV file '%s' for reading", file), err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	defer f.Close()
+
+	// read the CSV
+	csvReader := csv.NewReader(f)
+	csvReader.Comma = rune(separator[0])
+	dec, err := csvutil.NewDecoder(csvReader)
+	if err != nil {
+		errx := errors.NewGeneralFailure(fmt.Sprintf("failed to parse CSV file '%s'", file), err)
+		logger.Error().Err(errx).Msg(errx.Error())
+		return nil, errx
+	}
+	records := []Record[T]{}
+	for {
+		var value T
+		if err := dec.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			errx := errors.NewGeneralFailure("failed to decode record", err)
+			logger.Error().Err(errx).Msg(errx.Error())
+			return nil, errx
+		}
+		line, _ := csvReader.FieldPos(0)
+		columns := map[string]string{}
+		for i, column := range dec.Header() {
+			columns[column] = dec.Record()[i]
+		}
+		records = append(records, Record[T]{
+			Value:   value,
+			Line:    line,
+			Columns: columns,
+		})
+	}
+	return records, nil
+}
+
+// SplitList splits a comma-separated list, dropping any empty items.
+func SplitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
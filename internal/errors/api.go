@@ -2,10 +2,135 @@ package errors
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"go.joshhogle.dev/errorx"
 )
 
+// S1APIErrorDetail holds a single error returned in the body of an S1 API response.
+type S1APIErrorDetail struct {
+	// Code is the S1-specific error code.
+	Code uint64
+
+	// Detail is a more detailed description of the error, if any.
+	Detail string
+
+	// Title is a short description of the error.
+	Title string
+}
+
+// String returns the string version of the error detail.
+func (d S1APIErrorDetail) String() string {
+	if d.Detail != "" {
+		return fmt.Sprintf("%s: %s", d.Title, d.Detail)
+	}
+	return d.Title
+}
+
+// S1APIError occurs when the S1 API responds to a request with an error status code or returns error details in
+// the body of the response.
+type S1APIError struct {
+	*errorx.BaseError
+
+	// unexported variables
+	details    []S1APIErrorDetail
+	method     string
+	statusCode int
+	url        string
+}
+
+// NewS1APIError creates a new S1APIError error.
+func NewS1APIError(method, url string, statusCode int, details []S1APIErrorDetail) *S1APIError {
+	msgs := []string{}
+	for _, d := range details {
+		msgs = append(msgs, d.String())
+	}
+	if len(msgs) == 0 {
+		msgs = append(msgs, http.StatusText(statusCode))
+	}
+	e := &S1APIError{
+		BaseError:  errorx.NewBaseError(S1APIErrorCode, fmt.Errorf("%s", strings.Join(msgs, "; "))),
+		details:    details,
+		method:     method,
+		statusCode: statusCode,
+		url:        url,
+	}
+	e.WithAttrs(map[string]any{
+		"status_code": statusCode,
+	})
+	return e
+}
+
+// Details returns the list of errors returned in the body of the response.
+func (e *S1APIError) Details() []S1APIErrorDetail {
+	return e.details
+}
+
+// Error returns the string version of the error.
+func (e *S1APIError) Error() string {
+	return fmt.Sprintf("%s %s | server returned status code %d : %s", e.method, e.url, e.statusCode,
+		e.InternalError().Error())
+}
+
+// HasErrorCode returns whether or not the server returned the given S1-specific error code.
+func (e *S1APIError) HasErrorCode(code uint64) bool {
+	for _, d := range e.details {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsForbidden returns whether or not the API key is not allowed to perform the request.
+func (e *S1APIError) IsForbidden() bool {
+	return e.statusCode == http.StatusForbidden
+}
+
+// IsNotFound returns whether or not the requested object or endpoint does not exist.
+func (e *S1APIError) IsNotFound() bool {
+	return e.statusCode == http.StatusNotFound
+}
+
+// IsRateLimited returns whether or not the request was rejected because too many requests were sent.
+func (e *S1APIError) IsRateLimited() bool {
+	return e.statusCode == http.StatusTooManyRequests
+}
+
+// IsServerError returns whether or not the request failed because of an error on the server.
+func (e *S1APIError) IsServerError() bool {
+	return e.statusCode >= http.StatusInternalServerError
+}
+
+// IsUnauthorized returns whether or not the request was rejected because the API key is missing, invalid or
+// expired.
+func (e *S1APIError) IsUnauthorized() bool {
+	return e.statusCode == http.StatusUnauthorized
+}
+
+// IsValidationFailure returns whether or not the request was rejected because it contained invalid values.
+func (e *S1APIError) IsValidationFailure() bool {
+	return e.statusCode == http.StatusBadRequest || e.statusCode == http.StatusUnprocessableEntity
+}
+
+// Method returns just the HTTP method associated with the error.
+func (e *S1APIError) Method() string {
+	return e.method
+}
+
+// StatusCode returns the HTTP status code returned by the server.
+func (e *S1APIError) StatusCode() int {
+	return e.statusCode
+}
+
+// URL returns just the URL of the API called that is associated with the error.
+func (e *S1APIError) URL() string {
+	return e.url
+}
+
+// S1ClientError occurs when the S1 client fails to prepare a request or process its response.
 type S1ClientError struct {
 	*errorx.BaseError
 
@@ -14,8 +139,8 @@ type S1ClientError struct {
 }
 
 // NewS1ClientError creates a new S1ClientError error.
-func NewS1ClientError(msg string, err error) *S1ClientRequestError {
-	return &S1ClientRequestError{
+func NewS1ClientError(msg string, err error) *S1ClientError {
+	return &S1ClientError{
 		BaseError: errorx.NewBaseError(S1ClientErrorCode, err),
 		msg:       msg,
 	}
@@ -31,6 +156,7 @@ func (e *S1ClientError) Msg() string {
 	return e.msg
 }
 
+// S1ClientRequestError occurs when a request to the S1 API cannot be completed.
 type S1ClientRequestError struct {
 	*errorx.BaseError
 
@@ -69,3 +195,34 @@ func (e *S1ClientRequestError) Msg() string {
 func (e *S1ClientRequestError) URL() string {
 	return e.url
 }
+
+// S1APITokenExpired occurs when the API token being used to access the S1 API has expired.
+type S1APITokenExpired struct {
+	*errorx.BaseError
+
+	// unexported variables
+	expiresAt time.Time
+}
+
+// NewS1APITokenExpired creates a new S1APITokenExpired error.
+func NewS1APITokenExpired(expiresAt time.Time) *S1APITokenExpired {
+	e := &S1APITokenExpired{
+		BaseError: errorx.NewBaseError(S1APITokenExpiredCode, fmt.Errorf("token expired on %s",
+			expiresAt.Format(time.RFC3339))),
+		expiresAt: expiresAt,
+	}
+	e.WithAttrs(map[string]any{
+		"expires_at": expiresAt,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *S1APITokenExpired) Error() string {
+	return fmt.Sprintf("the API token can no longer be used : %s", e.InternalError().Error())
+}
+
+// ExpiresAt returns when the token expired.
+func (e *S1APITokenExpired) ExpiresAt() time.Time {
+	return e.expiresAt
+}
@@ -11,10 +11,22 @@ const (
 	ConfigLoadFailureCode     = 21
 	ConfigParseFailureCode    = 22
 	ConfigValidateFailureCode = 23
+	ConfigWriteFailureCode    = 24
 
 	// S1 client errors (101-120)
 	S1ClientErrorCode        = 101
 	S1ClientRequestErrorCode = 102
+	S1APIErrorCode           = 103
+	S1APITokenExpiredCode    = 104
+
+	// provisioning errors (121-140)
+	ProvisionFailureCode      = 121
+	RecordValidateFailureCode = 122
+
+	// secret errors (141-160)
+	SecretResolveFailureCode = 141
+	KeystoreFailureCode      = 142
+	SecretUpdateFailureCode  = 143
 
 	/*
 		// HTTP service errors (41-60)
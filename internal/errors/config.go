@@ -105,3 +105,20 @@ func (e *ConfigValidateFailure) Setting() string {
 func (e *ConfigValidateFailure) Value() any {
 	return e.value
 }
+
+// ConfigWriteFailure occurs when an error is detected while writing the configuration file.
+type ConfigWriteFailure struct {
+	*configBaseError
+}
+
+// NewConfigWriteFailure returns a new ConfigWriteFailure error.
+func NewConfigWriteFailure(configFile string, err error) *ConfigWriteFailure {
+	return &ConfigWriteFailure{
+		configBaseError: newConfigBaseError(configFile, ConfigWriteFailureCode, err),
+	}
+}
+
+// Error returns the string version of the error.
+func (e *ConfigWriteFailure) Error() string {
+	return fmt.Sprintf("error while writing configuration file '%s': %s", e.configFile, e.InternalError().Error())
+}
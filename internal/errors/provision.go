@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"go.joshhogle.dev/errorx"
+)
+
+// ProvisionFailure occurs when one or more records could not be provisioned.
+type ProvisionFailure struct {
+	*errorx.BaseError
+
+	// unexported variables
+	failed int
+	total  int
+}
+
+// NewProvisionFailure creates a new ProvisionFailure error.
+func NewProvisionFailure(failed, total int, err error) *ProvisionFailure {
+	e := &ProvisionFailure{
+		BaseError: errorx.NewBaseError(ProvisionFailureCode, err),
+		failed:    failed,
+		total:     total,
+	}
+	e.WithAttrs(map[string]any{
+		"failed": failed,
+		"total":  total,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *ProvisionFailure) Error() string {
+	return fmt.Sprintf("%d of %d records failed to provision: %s", e.failed, e.total, e.InternalError().Error())
+}
+
+// Failed returns the number of records which failed to provision.
+func (e *ProvisionFailure) Failed() int {
+	return e.failed
+}
+
+// Total returns the total number of records.
+func (e *ProvisionFailure) Total() int {
+	return e.total
+}
+
+// RecordValidateFailure occurs when one or more records fail validation before provisioning starts.
+type RecordValidateFailure struct {
+	*errorx.BaseError
+
+	// unexported variables
+	invalid  int
+	problems []string
+	total    int
+}
+
+// NewRecordValidateFailure creates a new RecordValidateFailure error.
+//
+// Each problem should identify the record it belongs to, such as by its line number.
+func NewRecordValidateFailure(invalid, total int, problems []string) *RecordValidateFailure {
+	e := &RecordValidateFailure{
+		BaseError: errorx.NewBaseError(RecordValidateFailureCode,
+			fmt.Errorf("%s", strings.Join(problems, "\n  "))),
+		invalid:  invalid,
+		problems: problems,
+		total:    total,
+	}
+	e.WithAttrs(map[string]any{
+		"invalid": invalid,
+		"total":   total,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *RecordValidateFailure) Error() string {
+	return fmt.Sprintf("%d of %d records are invalid:\n  %s", e.invalid, e.total, e.InternalError().Error())
+}
+
+// Invalid returns the number of records which are invalid.
+func (e *RecordValidateFailure) Invalid() int {
+	return e.invalid
+}
+
+// Problems returns every problem found with the records.
+func (e *RecordValidateFailure) Problems() []string {
+	return e.problems
+}
+
+// Total returns the total number of records.
+func (e *RecordValidateFailure) Total() int {
+	return e.total
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+
+	"go.joshhogle.dev/errorx"
+)
+
+// KeystoreFailure occurs when the local keystore cannot be opened, read or written.
+type KeystoreFailure struct {
+	*errorx.BaseError
+
+	// unexported variables
+	file string
+}
+
+// NewKeystoreFailure creates a new KeystoreFailure error.
+func NewKeystoreFailure(file string, err error) *KeystoreFailure {
+	e := &KeystoreFailure{
+		BaseError: errorx.NewBaseError(KeystoreFailureCode, err),
+		file:      file,
+	}
+	e.WithAttrs(map[string]any{
+		"keystore_file": file,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *KeystoreFailure) Error() string {
+	return fmt.Sprintf("error while accessing keystore '%s': %s", e.file, e.InternalError().Error())
+}
+
+// File returns the path to the keystore file.
+func (e *KeystoreFailure) File() string {
+	return e.file
+}
+
+// SecretResolveFailure occurs when a secret cannot be read from the source it refers to.
+type SecretResolveFailure struct {
+	*errorx.BaseError
+
+	// unexported variables
+	setting string
+}
+
+// NewSecretResolveFailure creates a new SecretResolveFailure error.
+func NewSecretResolveFailure(setting string, err error) *SecretResolveFailure {
+	e := &SecretResolveFailure{
+		BaseError: errorx.NewBaseError(SecretResolveFailureCode, err),
+		setting:   setting,
+	}
+	e.WithAttrs(map[string]any{
+		"setting": setting,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *SecretResolveFailure) Error() string {
+	return fmt.Sprintf("failed to read the secret for setting '%s': %s", e.setting, e.InternalError().Error())
+}
+
+// Setting returns the name of the setting whose secret could not be read.
+func (e *SecretResolveFailure) Setting() string {
+	return e.setting
+}
+
+// SecretUpdateFailure occurs when a secret cannot be replaced in the source it refers to.
+type SecretUpdateFailure struct {
+	*errorx.BaseError
+
+	// unexported variables
+	setting string
+}
+
+// NewSecretUpdateFailure creates a new SecretUpdateFailure error.
+func NewSecretUpdateFailure(setting string, err error) *SecretUpdateFailure {
+	e := &SecretUpdateFailure{
+		BaseError: errorx.NewBaseError(SecretUpdateFailureCode, err),
+		setting:   setting,
+	}
+	e.WithAttrs(map[string]any{
+		"setting": setting,
+	})
+	return e
+}
+
+// Error returns the string version of the error.
+func (e *SecretUpdateFailure) Error() string {
+	return fmt.Sprintf("failed to update the secret for setting '%s': %s", e.setting, e.InternalError().Error())
+}
+
+// Setting returns the name of the setting whose secret could not be updated.
+func (e *SecretUpdateFailure) Setting() string {
+	return e.setting
+}
@@ -0,0 +1,248 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Keystore settings.
+const (
+	_FormatVersion = 2
+	_Iterations    = 600000
+	_KeySize       = 32
+	_SaltSize      = 16
+
+	// _MaxIterations limits the work needed to unlock a keystore so that a tampered file cannot make Open() run for
+	// an unreasonable amount of time.
+	_MaxIterations = 10 * _Iterations
+
+	// _CheckValue is encrypted and stored in the file so that an incorrect passphrase can be detected even when the
+	// keystore holds no secrets.
+	_CheckValue = "s1cli-keystore"
+)
+
+var (
+	// ErrIncorrectPassphrase is returned when the keystore cannot be unlocked using the given passphrase.
+	ErrIncorrectPassphrase = goerrors.New("incorrect keystore passphrase")
+
+	// ErrSecretNotFound is returned when the keystore does not hold a secret with the given name.
+	ErrSecretNotFound = goerrors.New("secret not found in keystore")
+)
+
+// Keystore is a local file holding secrets which are encrypted using a key derived from a passphrase.
+//
+// Each secret is encrypted separately using AES-256-GCM with the name of the secret as additional data so that
+// encrypted values cannot be swapped between names. The encryption key is derived from the passphrase using PBKDF2
+// with HMAC-SHA256 and a random salt that is stored in the file.
+type Keystore struct {
+	// unexported variables
+	aead    cipher.AEAD
+	content keystoreFile
+	file    string
+}
+
+// keystoreFile is the structure of the keystore file on disk.
+type keystoreFile struct {
+	Check      sealedValue            `json:"check"`
+	Iterations int                    `json:"iterations"`
+	Salt       string                 `json:"salt"`
+	Secrets    map[string]sealedValue `json:"secrets"`
+	Version    int                    `json:"version"`
+}
+
+// sealedValue holds a single encrypted value.
+type sealedValue struct {
+	Ciphertext string `json:"ciphertext"`
+	Nonce      string `json:"nonce"`
+}
+
+// Open unlocks the keystore in the given file using the given passphrase.
+//
+// If the file does not exist, a new empty keystore is returned which is protected by the passphrase. The file is not
+// created until Save() is called.
+func Open(file, passphrase string) (*Keystore, error) {
+	if passphrase == "" {
+		return nil, goerrors.New("keystore passphrase cannot be empty")
+	}
+	ks := &Keystore{
+		file: file,
+	}
+
+	// create a new keystore
+	data, err := os.ReadFile(file)
+	if goerrors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, _SaltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		ks.content = keystoreFile{
+			Iterations: _Iterations,
+			Salt:       base64.StdEncoding.EncodeToString(salt),
+			Secrets:    map[string]sealedValue{},
+			Version:    _FormatVersion,
+		}
+		if ks.aead, err = newAEAD(passphrase, salt, _Iterations); err != nil {
+			return nil, err
+		}
+		if ks.content.Check, err = ks.seal("", _CheckValue); err != nil {
+			return nil, err
+		}
+		return ks, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// unlock the existing keystore
+	if err := json.Unmarshal(data, &ks.content); err != nil {
+		return nil, fmt.Errorf("keystore file is corrupt: %w", err)
+	}
+	if ks.content.Version != _FormatVersion {
+		return nil, fmt.Errorf("keystore file version %d is not supported", ks.content.Version)
+	}
+	salt, err := base64.StdEncoding.DecodeString(ks.content.Salt)
+	if err != nil || ks.content.Iterations <= 0 || ks.content.Iterations > _MaxIterations {
+		return nil, goerrors.New("keystore file is corrupt: invalid key derivation settings")
+	}
+	if ks.aead, err = newAEAD(passphrase, salt, ks.content.Iterations); err != nil {
+		return nil, err
+	}
+	if check, err := ks.open("", ks.content.Check); err != nil || check != _CheckValue {
+		return nil, ErrIncorrectPassphrase
+	}
+	if ks.content.Secrets == nil {
+		ks.content.Secrets = map[string]sealedValue{}
+	}
+	return ks, nil
+}
+
+// File returns the path to the keystore file.
+func (k *Keystore) File() string {
+	return k.file
+}
+
+// Get returns the secret with the given name.
+func (k *Keystore) Get(name string) (string, error) {
+	sealed, ok := k.content.Secrets[name]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	value, err := k.open(name, sealed)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret '%s': %w", name, err)
+	}
+	return value, nil
+}
+
+// Names returns the sorted names of all of the secrets in the keystore.
+func (k *Keystore) Names() []string {
+	names := make([]string, 0, len(k.content.Secrets))
+	for name := range k.content.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remove deletes the secret with the given name.
+func (k *Keystore) Remove(name string) error {
+	if _, ok := k.content.Secrets[name]; !ok {
+		return ErrSecretNotFound
+	}
+	delete(k.content.Secrets, name)
+	return nil
+}
+
+// Save writes the keystore to its file.
+//
+// The file is replaced atomically and is only readable by the current user.
+func (k *Keystore) Save() error {
+	data, err := json.MarshalIndent(&k.content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(k.file), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(k.file), filepath.Base(k.file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), k.file)
+}
+
+// Set stores the secret with the given name, replacing any existing secret with the same name.
+func (k *Keystore) Set(name, value string) error {
+	if name == "" {
+		return goerrors.New("secret name cannot be empty")
+	}
+	sealed, err := k.seal(name, value)
+	if err != nil {
+		return err
+	}
+	k.content.Secrets[name] = sealed
+	return nil
+}
+
+// open decrypts the value stored under the given name.
+func (k *Keystore) open(name string, sealed sealedValue) (string, error) {
+	nonce, err := base64.StdEncoding.DecodeString(sealed.Nonce)
+	if err != nil || len(nonce) != k.aead.NonceSize() {
+		return "", goerrors.New("invalid nonce")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed.Ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := k.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// seal encrypts the value stored under the given name using a new random nonce.
+//
+// The check value is not a secret and is sealed using an empty name, which a secret cannot have.
+func (k *Keystore) seal(name, value string) (sealedValue, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return sealedValue{}, err
+	}
+	return sealedValue{
+		Ciphertext: base64.StdEncoding.EncodeToString(k.aead.Seal(nil, nonce, []byte(value), []byte(name))),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+	}, nil
+}
+
+// newAEAD derives the encryption key from the passphrase and returns the cipher used to encrypt secrets.
+func newAEAD(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iterations, _KeySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,131 @@
+package keystore
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRoundTrip makes sure secrets which are saved can be read back using the same passphrase.
+func TestRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := Open(file, "passphrase")
+	if err != nil {
+		t.Fatalf("failed to create keystore: %s", err)
+	}
+	if err := ks.Set("prod", "prod-secret"); err != nil {
+		t.Fatalf("failed to set secret: %s", err)
+	}
+	if err := ks.Set("dev", "dev-secret"); err != nil {
+		t.Fatalf("failed to set secret: %s", err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatalf("failed to save keystore: %s", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("failed to stat keystore: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("keystore file permissions are %o rather than 600", perm)
+	}
+
+	ks, err = Open(file, "passphrase")
+	if err != nil {
+		t.Fatalf("failed to open keystore: %s", err)
+	}
+	for name, want := range map[string]string{"prod": "prod-secret", "dev": "dev-secret"} {
+		got, err := ks.Get(name)
+		if err != nil {
+			t.Fatalf("failed to get secret '%s': %s", name, err)
+		}
+		if got != want {
+			t.Errorf("secret '%s' is '%s' rather than '%s'", name, got, want)
+		}
+	}
+	if _, err := ks.Get("missing"); !goerrors.Is(err, ErrSecretNotFound) {
+		t.Errorf("getting a missing secret returned '%v' rather than ErrSecretNotFound", err)
+	}
+}
+
+// TestIncorrectPassphrase makes sure a keystore cannot be opened using the wrong passphrase.
+func TestIncorrectPassphrase(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := Open(file, "passphrase")
+	if err != nil {
+		t.Fatalf("failed to create keystore: %s", err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatalf("failed to save keystore: %s", err)
+	}
+	if _, err := Open(file, "wrong"); !goerrors.Is(err, ErrIncorrectPassphrase) {
+		t.Errorf("opening with the wrong passphrase returned '%v' rather than ErrIncorrectPassphrase", err)
+	}
+}
+
+// TestSwappedCiphertexts makes sure encrypted values cannot be moved from one secret to another in the file.
+func TestSwappedCiphertexts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := Open(file, "passphrase")
+	if err != nil {
+		t.Fatalf("failed to create keystore: %s", err)
+	}
+	ks.Set("prod", "prod-secret")
+	ks.Set("dev", "dev-secret")
+	if err := ks.Save(); err != nil {
+		t.Fatalf("failed to save keystore: %s", err)
+	}
+
+	// swap the encrypted values in the file
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read keystore: %s", err)
+	}
+	var content keystoreFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		t.Fatalf("failed to parse keystore: %s", err)
+	}
+	content.Secrets["prod"], content.Secrets["dev"] = content.Secrets["dev"], content.Secrets["prod"]
+	if data, err = json.Marshal(&content); err != nil {
+		t.Fatalf("failed to encode keystore: %s", err)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatalf("failed to write keystore: %s", err)
+	}
+
+	ks, err = Open(file, "passphrase")
+	if err != nil {
+		t.Fatalf("failed to open keystore: %s", err)
+	}
+	for _, name := range []string{"prod", "dev"} {
+		if value, err := ks.Get(name); err == nil {
+			t.Errorf("swapped secret '%s' was decrypted as '%s'", name, value)
+		}
+	}
+}
+
+// TestUnsupportedFile makes sure files with unsupported settings are rejected.
+func TestUnsupportedFile(t *testing.T) {
+	for name, change := range map[string]func(*keystoreFile){
+		"old version":         func(c *keystoreFile) { c.Version = 1 },
+		"too many iterations": func(c *keystoreFile) { c.Iterations = _MaxIterations + 1 },
+		"negative iterations": func(c *keystoreFile) { c.Iterations = -1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "keystore.json")
+			ks, err := Open(file, "passphrase")
+			if err != nil {
+				t.Fatalf("failed to create keystore: %s", err)
+			}
+			change(&ks.content)
+			if err := ks.Save(); err != nil {
+				t.Fatalf("failed to save keystore: %s", err)
+			}
+			if _, err := Open(file, "passphrase"); err == nil {
+				t.Error("keystore was opened")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package prompt
+
+import (
+	"bufio"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ValidateFn is called to validate an answer given by the user.
+//
+// If an error is returned, the error is shown to the user and the question is asked again.
+type ValidateFn func(answer string) error
+
+// Prompter asks the user questions and reads back their answers.
+type Prompter struct {
+	// unexported variables
+	in      *bufio.Reader
+	isStdin bool
+	out     io.Writer
+}
+
+// NewPrompter creates a new Prompter object which reads answers from in and writes questions to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	f, ok := in.(*os.File)
+	return &Prompter{
+		in:      bufio.NewReader(in),
+		isStdin: ok && f == os.Stdin,
+		out:     out,
+	}
+}
+
+// IsInteractive returns whether or not standard input is connected to a terminal.
+func IsInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Confirm asks the user a yes or no question.
+//
+// If the user simply presses ENTER, the default answer is returned.
+func (p *Prompter) Confirm(question string, defaultAnswer bool) (bool, error) {
+	choices := "y/N"
+	if defaultAnswer {
+		choices = "Y/n"
+	}
+	for {
+		answer, err := p.readLine(fmt.Sprintf("%s [%s]: ", question, choices))
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return defaultAnswer, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintf(p.out, "  please answer 'y' or 'n'\n")
+	}
+}
+
+// Int asks the user for a whole number.
+//
+// If the user simply presses ENTER, the default answer is used.
+func (p *Prompter) Int(question string, defaultAnswer int, validateFn ValidateFn) (int, error) {
+	answer, err := p.String(question, strconv.Itoa(defaultAnswer), func(answer string) error {
+		if _, err := strconv.Atoi(answer); err != nil {
+			return goerrors.New("value must be a whole number")
+		}
+		if validateFn != nil {
+			return validateFn(answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(answer)
+}
+
+// Secret asks the user for a value without showing it on the screen as it is typed.
+//
+// Hiding the input relies on the stty command. If it is not available, the value is shown as it is typed.
+func (p *Prompter) Secret(question string) (string, error) {
+	if p.isStdin && IsInteractive() {
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				stty("echo")
+				fmt.Fprintln(p.out)
+			}()
+		}
+	}
+	return p.readLine(fmt.Sprintf("%s: ", question))
+}
+
+// String asks the user for a string value.
+//
+// If the user simply presses ENTER, the default answer is used. The answer is asked for again until it passes
+// validation.
+func (p *Prompter) String(question, defaultAnswer string, validateFn ValidateFn) (string, error) {
+	label := fmt.Sprintf("%s: ", question)
+	if defaultAnswer != "" {
+		label = fmt.Sprintf("%s [%s]: ", question, defaultAnswer)
+	}
+	for {
+		answer, err := p.readLine(label)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = defaultAnswer
+		}
+		if validateFn == nil {
+			return answer, nil
+		}
+		if err := validateFn(answer); err != nil {
+			fmt.Fprintf(p.out, "  %s\n", err.Error())
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// readLine shows the label and reads a single line of input with surrounding whitespace removed.
+func (p *Prompter) readLine(label string) (string, error) {
+	fmt.Fprint(p.out, label)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// stty changes the settings of the terminal connected to standard input.
+func stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
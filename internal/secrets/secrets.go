@@ -0,0 +1,190 @@
+package secrets
+
+import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"go.joshhogle.dev/s1cli/internal/keystore"
+)
+
+// _SchemeSeparator separates the scheme of a secret reference from the rest of the reference.
+const _SchemeSeparator = "://"
+
+// Source looks up secrets referred to using a particular scheme (eg: env://MY_VAR).
+type Source interface {
+	// Scheme returns the scheme handled by the source (eg: env).
+	Scheme() string
+
+	// Resolve returns the secret for the given reference, which has already had the scheme removed.
+	Resolve(ref string) (string, error)
+}
+
+// Resolver converts secret references into the actual secret using the appropriate Source.
+type Resolver struct {
+	// unexported variables
+	sources map[string]Source
+}
+
+// NewResolver creates a new Resolver object which handles the given sources.
+func NewResolver(sources ...Source) *Resolver {
+	r := &Resolver{
+		sources: map[string]Source{},
+	}
+	for _, source := range sources {
+		r.sources[source.Scheme()] = source
+	}
+	return r
+}
+
+// Resolve returns the secret for the given value.
+//
+// If the value is not a reference, the value itself is the secret and is returned unchanged.
+func (r *Resolver) Resolve(value string) (string, error) {
+	scheme := Scheme(value)
+	if scheme == "" {
+		return value, nil
+	}
+	source, ok := r.sources[scheme]
+	if !ok {
+		return "", fmt.Errorf("unsupported secret reference '%s%s'; must be one of: %s", scheme, _SchemeSeparator,
+			strings.Join(r.schemes(), ", "))
+	}
+	secret, err := source.Resolve(strings.TrimPrefix(value, scheme+_SchemeSeparator))
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("secret referred to by '%s%s' is empty", scheme, _SchemeSeparator)
+	}
+	return secret, nil
+}
+
+// schemes returns the sorted list of schemes handled by the resolver.
+func (r *Resolver) schemes() []string {
+	schemes := make([]string, 0, len(r.sources))
+	for scheme := range r.sources {
+		schemes = append(schemes, scheme+_SchemeSeparator)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Scheme returns the scheme of the given secret reference or an empty string if the value is not a reference.
+func Scheme(value string) string {
+	scheme, _, found := strings.Cut(value, _SchemeSeparator)
+	if !found || scheme == "" {
+		return ""
+	}
+	for _, r := range scheme {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	return scheme
+}
+
+// CommandSource reads secrets from the output of a command (eg: cmd://pass show s1/api-key).
+//
+// The command is run using the system shell and any trailing newlines in its output are removed.
+type CommandSource struct{}
+
+// Scheme returns the scheme handled by the source.
+func (s CommandSource) Scheme() string {
+	return "cmd"
+}
+
+// Resolve runs the command and returns its output.
+func (s CommandSource) Resolve(ref string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", ref)
+	} else {
+		cmd = exec.Command("sh", "-c", ref)
+	}
+	var stderr bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("secret command failed: %w", err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// EnvSource reads secrets from environment variables (eg: env://S1_API_KEY).
+type EnvSource struct{}
+
+// Scheme returns the scheme handled by the source.
+func (s EnvSource) Scheme() string {
+	return "env"
+}
+
+// Resolve returns the value of the environment variable.
+func (s EnvSource) Resolve(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable '%s' is not set", ref)
+	}
+	return value, nil
+}
+
+// FileSource reads secrets from files (eg: file:///home/me/.s1/api-key).
+//
+// Environment variables in the path are expanded and any trailing newlines in the file are removed.
+type FileSource struct{}
+
+// Scheme returns the scheme handled by the source.
+func (s FileSource) Scheme() string {
+	return "file"
+}
+
+// Resolve returns the contents of the file.
+func (s FileSource) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(os.ExpandEnv(ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// KeystoreSource reads secrets from the local encrypted keystore (eg: keystore://prod).
+type KeystoreSource struct {
+	// OpenFn is called to unlock the keystore the first time a secret is needed.
+	OpenFn func() (*keystore.Keystore, error)
+
+	// unexported variables
+	ks *keystore.Keystore
+}
+
+// Scheme returns the scheme handled by the source.
+func (s *KeystoreSource) Scheme() string {
+	return "keystore"
+}
+
+// Resolve returns the secret with the given name from the keystore.
+func (s *KeystoreSource) Resolve(ref string) (string, error) {
+	if s.ks == nil {
+		if s.OpenFn == nil {
+			return "", goerrors.New("keystore is not available")
+		}
+		ks, err := s.OpenFn()
+		if err != nil {
+			return "", err
+		}
+		s.ks = ks
+	}
+	secret, err := s.ks.Get(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s' from keystore '%s': %w", ref, s.ks.File(), err)
+	}
+	return secret, nil
+}